@@ -6,13 +6,9 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
-)
 
-type checkResult struct {
-	Valid   bool   `json:"valid"`
-	Message string `json:"message"`
-	Error   string `json:"error,omitempty"`
-}
+	"tfl/internal/display"
+)
 
 var checkCmd = &cobra.Command{
 	Use:   "check",
@@ -25,10 +21,11 @@ Examples:
   tfl check
   tfl check --key YOUR_API_KEY
   tfl check --format json`,
+	Annotations: map[string]string{formatsAnnotation: "text,json"},
 	Run: func(cmd *cobra.Command, args []string) {
 		if !client.HasKey() {
 			if IsJSON() {
-				printCheckResult(checkResult{Valid: false, Message: "No API key configured"})
+				printCheckResult(display.CheckOutput{Valid: false, Message: "No API key configured"})
 			} else {
 				fmt.Fprintln(os.Stderr, "No API key configured.")
 				fmt.Fprintln(os.Stderr, "Set TFL_APP_KEY environment variable or use --key flag.")
@@ -42,7 +39,7 @@ Examples:
 
 		if err := client.ValidateKey(); err != nil {
 			if IsJSON() {
-				printCheckResult(checkResult{Valid: false, Message: "API key validation failed", Error: err.Error()})
+				printCheckResult(display.CheckOutput{Valid: false, Message: "API key validation failed", Error: err.Error()})
 			} else {
 				fmt.Fprintln(os.Stderr, "failed")
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -51,14 +48,14 @@ Examples:
 		}
 
 		if IsJSON() {
-			printCheckResult(checkResult{Valid: true, Message: "API key is valid"})
+			printCheckResult(display.CheckOutput{Valid: true, Message: "API key is valid"})
 		} else {
 			fmt.Println("valid")
 		}
 	},
 }
 
-func printCheckResult(result checkResult) {
+func printCheckResult(result display.CheckOutput) {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	_ = enc.Encode(result)
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -12,11 +14,23 @@ import (
 
 	"tfl/internal/display"
 	"tfl/internal/tfl"
+	"tfl/internal/timeexpr"
 )
 
 var limit int
 var match string
 var departureTime string
+var departureUntil string
+var departureWindow time.Duration
+var filter arrivalFilter
+var verbose bool
+var jsonCompat bool
+var blend bool
+var groupBy string
+var perGroup int
+var departuresStyle string
+var watch bool
+var watchInterval time.Duration
 
 var departuresCmd = &cobra.Command{
 	Use:   "departures <station-name>",
@@ -25,91 +39,200 @@ var departuresCmd = &cobra.Command{
 
 Station names are matched case-insensitively and support partial matching.
 Use quotes for station names containing spaces. Use -m to filter by line or destination.
+A stop ID (as printed by search or nearby) can be given instead of a name.
+
+For precise filtering, --line, --destination, --platform and --direction
+each take one or more values (repeat the flag or separate with commas). A
+departure must match one value of every filter given; --exclude drops
+departures whose line, destination or platform matches. Values match as
+case-insensitive substrings, a bare platform number matches that platform
+only, and --regex treats values as regular expressions. --direction matches
+inbound or outbound, or the bound in the platform name (e.g. southbound).
+
+Real-time predictions only reach about 30 minutes ahead. With --blend, the
+board continues past that with timetable departures for each line and
+direction, skipping scheduled trips that already appear as live ones.
+
+--until or --window ends the list at a given time, e.g. all trains between
+17:30 and 18:15. Without either, timetable departures stop 4 hours after
+--time. A window that runs past the reach of real-time predictions is
+filled in from the timetable, as with --blend.
+
+Use --group-by to show a section per platform, line or direction, like the
+platform boards at big stations, with --per-group limiting each section.
+
+--style board draws an amber station board per platform with the next three
+trains. --watch redraws the departures every --interval; with the board
+style it also shows the first train's calling points, paging through them
+between refreshes.
 
 Examples:
   tfl departures "Liverpool Street"
   tfl departures Paddington
+  tfl departures 940GZZLUKSX
   tfl departures Paddington -n 5
   tfl departures Paddington -m Central
   tfl departures Paddington -m "Heathrow Terminal 5"
+  tfl departures "Kings Cross" --line piccadilly --exclude uxbridge
+  tfl departures Stratford --platform 3,4
+  tfl departures "Baker Street" --line jubilee --direction southbound
+  tfl departures Bank --regex --destination '^(morden|kennington)$'
   tfl departures Paddington --time 14:30
-  tfl departures Paddington --format json`,
+  tfl departures Paddington --time "+20m"
+  tfl departures Paddington --time "next monday 8am" --until 9am
+  tfl departures Paddington --time 17:30 --window 45m
+  tfl departures Paddington -n 20 --blend
+  tfl departures "Kings Cross" --group-by platform --per-group 3
+  tfl departures Paddington --style board --watch
+  tfl departures Paddington --verbose
+  tfl departures Paddington --format json
+  tfl departures Paddington --format json --json-compat
+  tfl departures Paddington --format csv --columns line,destination,minutes_away`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		stationQuery := args[0]
+		if groupBy != "" && !isValidGroupBy(groupBy) {
+			fmt.Fprintf(os.Stderr, "Error: unknown --group-by '%s' (use %s)\n", groupBy, strings.Join(display.GroupByFields, ", "))
+			os.Exit(1)
+		}
+		if perGroup < 0 {
+			fmt.Fprintln(os.Stderr, "Error: --per-group must not be negative")
+			os.Exit(1)
+		}
+		if groupBy != "" && (IsNDJSON() || IsTable() || (IsJSON() && jsonCompat)) {
+			fmt.Fprintln(os.Stderr, "Error: --group-by is only supported with text, json and template output")
+			os.Exit(1)
+		}
+		if departuresStyle != "text" && departuresStyle != "board" {
+			fmt.Fprintln(os.Stderr, "Error: --style must be text or board")
+			os.Exit(1)
+		}
+		if departuresStyle == "board" && groupBy != "" {
+			fmt.Fprintln(os.Stderr, "Error: --group-by can't be used with --style board, which always shows a board per platform")
+			os.Exit(1)
+		}
+		if watch && (outputFormat != "text" || IsTemplate()) {
+			fmt.Fprintln(os.Stderr, "Error: --watch is only supported with text output")
+			os.Exit(1)
+		}
+		if watch && watchInterval < 5*time.Second {
+			fmt.Fprintln(os.Stderr, "Error: --interval must be at least 5s")
+			os.Exit(1)
+		}
 
-		stops, err := client.SearchStopPoints(stationQuery)
+		found, err := resolveStop(args[0])
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error searching stations: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+		stop := found.Stop
+
+		q := departureQuery{
+			Match:  match,
+			Time:   departureTime,
+			Until:  departureUntil,
+			Window: departureWindow,
+			Limit:  limit,
+			Blend:  blend,
+			Filter: filter,
+		}
+		if watch {
+			watchDepartures(found, q)
+			return
+		}
 
-		if len(stops) == 0 {
-			fmt.Fprintf(os.Stderr, "No stations found matching '%s'\n", stationQuery)
+		result, err := getDepartures(stop.ID, q)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+		arrivals := result.Arrivals
 
-		stop := selectBestMatch(stops, stationQuery)
-
-		var arrivals []tfl.Arrival
-		var minTime time.Time
-
-		if departureTime != "" {
-			minTime, err = parseTimeToday(departureTime)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error parsing time: %v\n", err)
-				os.Exit(1)
-			}
+		if result.FellBack && outputFormat == "text" && !IsTemplate() {
+			fmt.Println("Note: Timetable unavailable for this line. Real-time data only covers ~30 minutes ahead.")
 		}
 
-		// Use timetable if time is more than 30 minutes in the future
-		useTimetable := departureTime != "" && time.Until(minTime) > 30*time.Minute
-		timetableFailed := false
-
-		if useTimetable {
-			arrivals, err = getArrivalsFromTimetable(stop.ID, match, minTime)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error fetching timetable: %v\n", err)
-				os.Exit(1)
-			}
-			if len(arrivals) == 0 {
-				timetableFailed = true
-			}
+		ctx := departuresContext(args[0], found, result)
+		ctx.GroupBy, ctx.PerGroup = groupBy, perGroup
+
+		switch {
+		case IsJSON() && jsonCompat:
+			display.PrintArrivalsJSONV1(arrivals, stop.Name)
+		case IsJSON():
+			display.PrintArrivalsJSON(arrivals, ctx)
+		case IsNDJSON():
+			display.PrintArrivalsNDJSON(arrivals, ctx)
+		case IsTemplate():
+			display.PrintTemplate(outputTemplate, display.NewDeparturesOutput(arrivals, ctx))
+		case IsTable():
+			printTable(display.ArrivalsTable(arrivals, stop.Name))
+		case departuresStyle == "board":
+			display.PrintBoards(display.NewBoards(arrivals), stop.Name, 0)
+		default:
+			printTextDepartures(arrivals, stop.Name)
 		}
+	},
+}
 
-		// Fall back to real-time arrivals if timetable returned no results
-		// (e.g., Elizabeth line doesn't support timetable API)
-		if !useTimetable || timetableFailed {
-			if timetableFailed {
-				fmt.Println("Note: Timetable unavailable for this line. Real-time data only covers ~30 minutes ahead.")
-			}
+func printTextDepartures(arrivals []tfl.Arrival, stationName string) {
+	if groupBy != "" {
+		groups, _ := display.GroupArrivals(arrivals, groupBy, perGroup)
+		display.PrintArrivalGroups(groups, stationName, verbose)
+		return
+	}
+	display.PrintArrivals(arrivals, stationName, verbose)
+}
 
-			arrivals, err = client.GetAllArrivalsAtStop(stop.ID)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error fetching arrivals: %v\n", err)
-				os.Exit(1)
-			}
+// boardPageInterval is how long each page of a board's calling points is
+// shown in watch mode.
+const boardPageInterval = 4 * time.Second
 
-			// Apply time filter - this may result in no arrivals if time is far in future
-			if departureTime != "" {
-				arrivals = filterByTime(arrivals, minTime)
-			}
+// watchDepartures redraws the departures every --interval until
+// interrupted. Fetch errors are reported and retried at the next refresh.
+func watchDepartures(found stopMatch, q departureQuery) {
+	for {
+		next := time.Now().Add(watchInterval)
+		result, err := getDepartures(found.Stop.ID, q)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			time.Sleep(time.Until(next))
+			continue
 		}
 
-		if match != "" {
-			arrivals = filterByMatch(arrivals, match)
+		if departuresStyle != "board" {
+			fmt.Print("\033[H\033[2J")
+			printTextDepartures(result.Arrivals, found.Stop.Name)
+			time.Sleep(time.Until(next))
+			continue
 		}
 
-		if limit > 0 && len(arrivals) > limit {
-			arrivals = arrivals[:limit]
+		boards := display.NewBoards(result.Arrivals)
+		addCallingPoints(boards)
+		for page := 0; time.Now().Before(next); page++ {
+			fmt.Print("\033[H\033[2J")
+			display.PrintBoards(boards, found.Stop.Name, page)
+			wait := time.Until(next)
+			if wait > boardPageInterval {
+				wait = boardPageInterval
+			}
+			time.Sleep(wait)
 		}
+	}
+}
 
-		if IsJSON() {
-			display.PrintArrivalsJSON(arrivals, stop.Name)
-		} else {
-			display.PrintArrivals(arrivals, stop.Name)
+// addCallingPoints fills in the calling points of each board's first
+// train from its vehicle predictions, where the train is tracked.
+func addCallingPoints(boards []display.Board) {
+	for i := range boards {
+		first := boards[i].Arrivals[0]
+		if first.VehicleID == "" {
+			continue
 		}
-	},
+		vehicleArrivals, err := client.GetVehicleArrivals(first.VehicleID)
+		if err != nil {
+			continue
+		}
+		boards[i].CallingPoints = display.CallingPoints(first, vehicleArrivals)
+	}
 }
 
 var searchCmd = &cobra.Command{
@@ -123,7 +246,8 @@ Examples:
   tfl search "King's Cross"
   tfl search Paddington
   tfl search Victoria
-  tfl search Liverpool --format json`,
+  tfl search Liverpool --format json
+  tfl search Liverpool --format tsv`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		stops, err := client.SearchStopPoints(args[0])
@@ -131,14 +255,331 @@ Examples:
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		if IsJSON() {
+		switch {
+		case IsJSON():
 			display.PrintStopPointsJSON(stops)
-		} else {
+		case IsNDJSON():
+			display.PrintStopPointsNDJSON(stops, args[0], time.Now())
+		case IsTemplate():
+			display.PrintTemplate(outputTemplate, display.NewStopPointsOutput(stops))
+		case IsTable():
+			printTable(display.StopPointsTable(stops))
+		default:
 			display.PrintStopPoints(stops)
 		}
 	},
 }
 
+func isValidGroupBy(field string) bool {
+	for _, f := range display.GroupByFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// stopIDPattern matches NaPTAN stop IDs such as 940GZZLUKSX, 910GPADTON,
+// 490000173RF and hub IDs such as HUBKGX.
+var stopIDPattern = regexp.MustCompile(`^(HUB[A-Z0-9]{3,}|[0-9]{3}[0-9A-Z]+)$`)
+
+func isStopID(query string) bool {
+	return stopIDPattern.MatchString(query)
+}
+
+// departureQuery holds the departure options shared by the departures
+// command and the server modes.
+type departureQuery struct {
+	Match  string
+	Time   string
+	Until  string
+	Window time.Duration
+	Limit  int
+	Blend  bool
+	Filter arrivalFilter
+}
+
+// bounds parses the query's --time and --until expressions, or applies
+// --window. Either is zero when not given. --until is read relative to
+// --time, so "9am" is on the same day and "+45m" is 45 minutes after it.
+func (q departureQuery) bounds(now time.Time) (from, until time.Time, err error) {
+	if q.Time != "" {
+		if from, err = timeexpr.Parse(q.Time, now); err != nil {
+			return from, until, err
+		}
+	}
+	start := from
+	if start.IsZero() {
+		start = now
+	}
+	if q.Window != 0 {
+		if q.Until != "" {
+			return from, until, fmt.Errorf("use either until or window, not both")
+		}
+		if q.Window < 0 {
+			return from, until, fmt.Errorf("window must be positive")
+		}
+		return from, start.Add(q.Window), nil
+	}
+	if q.Until != "" {
+		if until, err = timeexpr.Parse(q.Until, start); err != nil {
+			return from, until, err
+		}
+		if !until.After(start) {
+			return from, until, fmt.Errorf("until (%s) must be after %s", until.Format("Mon 15:04"), start.Format("Mon 15:04"))
+		}
+	}
+	return from, until, nil
+}
+
+// realtimeHorizon is roughly how far ahead real-time predictions reach.
+const realtimeHorizon = 30 * time.Minute
+
+// defaultTimetableWindow is how far past the requested time timetable
+// departures are listed when no --until or --window is given.
+const defaultTimetableWindow = 4 * time.Hour
+
+// sourceBlended is the departuresResult source when live predictions are
+// continued with timetable departures.
+const sourceBlended = "blended"
+
+// departuresResult is the outcome of getDepartures. Source says whether
+// the arrivals are live predictions or timetable entries; FellBack reports
+// that a timetable lookup was needed but unavailable, so only real-time
+// data was used.
+type departuresResult struct {
+	Arrivals  []tfl.Arrival
+	Source    string
+	FellBack  bool
+	FetchedAt time.Time
+}
+
+// getDepartures fetches arrivals at stopID and applies the query's time,
+// match and limit options.
+func getDepartures(stopID string, q departureQuery) (departuresResult, error) {
+	var arrivals []tfl.Arrival
+
+	minTime, until, err := q.bounds(time.Now())
+	if err != nil {
+		return departuresResult{}, err
+	}
+	keep, err := q.Filter.matcher()
+	if err != nil {
+		return departuresResult{}, err
+	}
+
+	// Use timetable if time is beyond real-time predictions
+	useTimetable := q.Time != "" && time.Until(minTime) > realtimeHorizon
+
+	// A window that starts within real-time range but runs past it needs
+	// both sources.
+	if q.Blend || (!useTimetable && !until.IsZero() && time.Until(until) > realtimeHorizon) {
+		return getBlendedDepartures(stopID, q, minTime, until, keep)
+	}
+	timetableFailed := false
+
+	if useTimetable {
+		arrivals, err = getArrivalsFromTimetable(stopID, q.Match, minTime, until)
+		if err != nil {
+			return departuresResult{}, fmt.Errorf("fetching timetable: %w", err)
+		}
+		if len(arrivals) == 0 {
+			timetableFailed = true
+		}
+	}
+
+	// Fall back to real-time arrivals if timetable returned no results
+	// (e.g., Elizabeth line doesn't support timetable API)
+	if !useTimetable || timetableFailed {
+		arrivals, err = client.GetAllArrivalsAtStop(stopID)
+		if err != nil {
+			return departuresResult{}, fmt.Errorf("fetching arrivals: %w", err)
+		}
+
+		for i := range arrivals {
+			arrivals[i].Source = tfl.SourceRealtime
+		}
+
+		// Apply time filter - this may result in no arrivals if time is far in future
+		if q.Time != "" {
+			arrivals = filterByTime(arrivals, minTime)
+		}
+	}
+
+	arrivals = applyFilters(filterUntil(arrivals, until), q, keep)
+
+	source := tfl.SourceRealtime
+	if useTimetable && !timetableFailed {
+		source = tfl.SourceTimetable
+	}
+
+	return departuresResult{
+		Arrivals:  arrivals,
+		Source:    source,
+		FellBack:  timetableFailed,
+		FetchedAt: time.Now(),
+	}, nil
+}
+
+// getBlendedDepartures combines live predictions with timetable departures
+// that fall after them. A missing timetable (e.g. Elizabeth line) leaves
+// the live predictions on their own.
+func getBlendedDepartures(stopID string, q departureQuery, minTime, until time.Time, keep func(tfl.Arrival) bool) (departuresResult, error) {
+	live, err := client.GetAllArrivalsAtStop(stopID)
+	if err != nil {
+		return departuresResult{}, fmt.Errorf("fetching arrivals: %w", err)
+	}
+	for i := range live {
+		live[i].Source = tfl.SourceRealtime
+	}
+	if q.Time != "" {
+		live = filterByTime(live, minTime)
+	}
+
+	from := minTime
+	if from.IsZero() {
+		from = time.Now()
+	}
+	// Match is applied to the merged list, since it may name a destination
+	// rather than a line.
+	scheduled, err := getArrivalsFromTimetable(stopID, "", from, until)
+	if err != nil {
+		return departuresResult{}, fmt.Errorf("fetching timetable: %w", err)
+	}
+
+	return departuresResult{
+		Arrivals:  applyFilters(filterUntil(blendArrivals(live, scheduled), until), q, keep),
+		Source:    sourceBlended,
+		FellBack:  len(scheduled) == 0,
+		FetchedAt: time.Now(),
+	}, nil
+}
+
+// blendTolerance is how far a live prediction may drift from its timetabled
+// time and still be treated as the same trip.
+const blendTolerance = 2 * time.Minute
+
+// blendArrivals merges live and scheduled arrivals into one list sorted by
+// time. For each line and direction, scheduled trips are used only after
+// the last live prediction, since live data is more accurate where it
+// exists. Lines with no live predictions continue after the overall live
+// horizon. A scheduled trip within blendTolerance of a live one on the same
+// line and destination is taken to be that trip and dropped.
+func blendArrivals(live, scheduled []tfl.Arrival) []tfl.Arrival {
+	key := func(a tfl.Arrival) string {
+		return a.LineID + "\x00" + strings.ToLower(a.Direction)
+	}
+
+	var horizon time.Time
+	horizons := make(map[string]time.Time)
+	for _, a := range live {
+		if a.ExpectedArrival.After(horizons[key(a)]) {
+			horizons[key(a)] = a.ExpectedArrival
+		}
+		if a.ExpectedArrival.After(horizon) {
+			horizon = a.ExpectedArrival
+		}
+	}
+
+	blended := append([]tfl.Arrival(nil), live...)
+	for _, s := range scheduled {
+		h, ok := horizons[key(s)]
+		if !ok {
+			h = horizon
+		}
+		if !s.ExpectedArrival.After(h) || matchesLiveTrip(s, live) {
+			continue
+		}
+		blended = append(blended, s)
+	}
+
+	sort.SliceStable(blended, func(i, j int) bool {
+		return blended[i].ExpectedArrival.Before(blended[j].ExpectedArrival)
+	})
+	return blended
+}
+
+func matchesLiveTrip(s tfl.Arrival, live []tfl.Arrival) bool {
+	for _, a := range live {
+		if a.LineID != s.LineID || !strings.EqualFold(a.DestinationName, s.DestinationName) {
+			continue
+		}
+		diff := a.ExpectedArrival.Sub(s.ExpectedArrival)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff <= blendTolerance {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFilters applies the -m match, the structured filters compiled into
+// keep, and the limit.
+func applyFilters(arrivals []tfl.Arrival, q departureQuery, keep func(tfl.Arrival) bool) []tfl.Arrival {
+	if q.Match != "" {
+		arrivals = filterByMatch(arrivals, q.Match)
+	}
+	var kept []tfl.Arrival
+	for _, a := range arrivals {
+		if keep(a) {
+			kept = append(kept, a)
+		}
+	}
+	arrivals = kept
+	if q.Limit > 0 && len(arrivals) > q.Limit {
+		arrivals = arrivals[:q.Limit]
+	}
+	return arrivals
+}
+
+// stopMatch is a resolved stop and the search results it was chosen from.
+// errNoStations is returned by resolveStop when a search finds nothing, as
+// opposed to the search itself failing.
+var errNoStations = errors.New("no stations found")
+
+type stopMatch struct {
+	Stop       tfl.StopPoint
+	Candidates []tfl.StopPoint
+}
+
+// resolveStop resolves a stop ID or a station name to a single stop.
+func resolveStop(query string) (stopMatch, error) {
+	if isStopID(query) {
+		detail, err := client.GetStopPointDetails(query)
+		if err != nil {
+			return stopMatch{}, fmt.Errorf("looking up stop %s: %w", query, err)
+		}
+		return stopMatch{Stop: tfl.StopPoint{ID: detail.ID, Name: detail.Name}}, nil
+	}
+
+	stops, err := client.SearchStopPoints(query)
+	if err != nil {
+		return stopMatch{}, fmt.Errorf("searching stations: %w", err)
+	}
+	if len(stops) == 0 {
+		return stopMatch{}, fmt.Errorf("%w matching '%s'", errNoStations, query)
+	}
+	return stopMatch{Stop: selectBestMatch(stops, query), Candidates: stops}, nil
+}
+
+func findStop(query string) (tfl.StopPoint, error) {
+	found, err := resolveStop(query)
+	return found.Stop, err
+}
+
+func departuresContext(query string, found stopMatch, result departuresResult) display.DeparturesContext {
+	return display.DeparturesContext{
+		Station:    found.Stop.Name,
+		StopID:     found.Stop.ID,
+		Query:      query,
+		Candidates: found.Candidates,
+		Source:     result.Source,
+		FetchedAt:  result.FetchedAt,
+	}
+}
+
 func filterByMatch(arrivals []tfl.Arrival, match string) []tfl.Arrival {
 	words := strings.Fields(strings.ToLower(match))
 	var filtered []tfl.Arrival
@@ -158,13 +599,18 @@ func filterByMatch(arrivals []tfl.Arrival, match string) []tfl.Arrival {
 	return filtered
 }
 
-func parseTimeToday(timeStr string) (time.Time, error) {
-	t, err := time.Parse("15:04", timeStr)
-	if err != nil {
-		return time.Time{}, fmt.Errorf("invalid time format, use HH:MM (e.g., 14:30)")
+// filterUntil drops arrivals after until, unless until is zero.
+func filterUntil(arrivals []tfl.Arrival, until time.Time) []tfl.Arrival {
+	if until.IsZero() {
+		return arrivals
+	}
+	var filtered []tfl.Arrival
+	for _, a := range arrivals {
+		if !a.ExpectedArrival.After(until) {
+			filtered = append(filtered, a)
+		}
 	}
-	now := time.Now()
-	return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location()), nil
+	return filtered
 }
 
 func filterByTime(arrivals []tfl.Arrival, minTime time.Time) []tfl.Arrival {
@@ -195,7 +641,7 @@ func selectBestMatch(stops []tfl.StopPoint, query string) tfl.StopPoint {
 	return stops[0]
 }
 
-func getArrivalsFromTimetable(stopID, lineFilter string, minTime time.Time) ([]tfl.Arrival, error) {
+func getArrivalsFromTimetable(stopID, lineFilter string, minTime, until time.Time) ([]tfl.Arrival, error) {
 	detail, err := client.GetStopPointDetails(stopID)
 	if err != nil {
 		return nil, err
@@ -268,7 +714,7 @@ func getArrivalsFromTimetable(stopID, lineFilter string, minTime time.Time) ([]t
 
 	// Second pass: parse all timetables with complete station names
 	for _, timetable := range timetables {
-		arrivals := parseTimetableWithStations(timetable, minTime, stationNames)
+		arrivals := parseTimetableWithStations(timetable, minTime, until, stationNames)
 		allArrivals = append(allArrivals, arrivals...)
 	}
 
@@ -280,13 +726,21 @@ func getArrivalsFromTimetable(stopID, lineFilter string, minTime time.Time) ([]t
 	return allArrivals, nil
 }
 
-func parseTimetableWithStations(tt *tfl.TimetableResponse, minTime time.Time, stationNames map[string]string) []tfl.Arrival {
+// parseTimetableWithStations returns the journeys in tt from minTime up to
+// until, or for defaultTimetableWindow after minTime if until is zero.
+func parseTimetableWithStations(tt *tfl.TimetableResponse, minTime, until time.Time, stationNames map[string]string) []tfl.Arrival {
 	if tt == nil || len(tt.Timetable.Routes) == 0 {
 		return nil
 	}
 
-	now := time.Now()
-	today := now.Weekday()
+	// Journeys are for the day of minTime, which may not be today.
+	day := minTime
+	if day.IsZero() {
+		day = time.Now()
+	}
+	if until.IsZero() {
+		until = day.Add(defaultTimetableWindow)
+	}
 	var arrivals []tfl.Arrival
 
 	for _, route := range tt.Timetable.Routes {
@@ -306,7 +760,7 @@ func parseTimetableWithStations(tt *tfl.TimetableResponse, minTime time.Time, st
 		}
 
 		for _, schedule := range route.Schedules {
-			if !scheduleMatchesDay(schedule.Name, today) {
+			if !scheduleMatchesDay(schedule.Name, day.Weekday()) {
 				continue
 			}
 
@@ -314,16 +768,15 @@ func parseTimetableWithStations(tt *tfl.TimetableResponse, minTime time.Time, st
 				hour, _ := strconv.Atoi(journey.Hour)
 				minute, _ := strconv.Atoi(journey.Minute)
 
-				departTime := time.Date(now.Year(), now.Month(), now.Day(),
-					hour, minute, 0, 0, now.Location())
+				departTime := time.Date(day.Year(), day.Month(), day.Day(),
+					hour, minute, 0, 0, day.Location())
 
 				// Skip departures before the requested time
 				if departTime.Before(minTime) {
 					continue
 				}
 
-				// Skip departures more than 4 hours after the requested time
-				if departTime.After(minTime.Add(4 * time.Hour)) {
+				if departTime.After(until) {
 					continue
 				}
 
@@ -336,8 +789,10 @@ func parseTimetableWithStations(tt *tfl.TimetableResponse, minTime time.Time, st
 					LineName:        tt.LineName,
 					LineID:          tt.LineID,
 					DestinationName: dest,
+					Direction:       tt.Direction,
 					ExpectedArrival: departTime,
 					TimeToStation:   int(time.Until(departTime).Seconds()),
+					Source:          tfl.SourceTimetable,
 				})
 			}
 		}
@@ -389,7 +844,23 @@ func formatStopID(stopID string) string {
 func init() {
 	departuresCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Maximum number of departures to show")
 	departuresCmd.Flags().StringVarP(&match, "match", "m", "", "Fuzzy filter by line name and/or destination")
-	departuresCmd.Flags().StringVarP(&departureTime, "time", "t", "", "Show departures at or after this time (HH:MM)")
+	departuresCmd.Flags().StringVarP(&departureTime, "time", "t", "", "Show departures at or after this time (e.g. 14:30, 9am, +20m, tomorrow 08:00)")
+	departuresCmd.Flags().DurationVar(&departureWindow, "window", 0, "Show departures for this long after --time (e.g. 90m)")
+	departuresCmd.Flags().StringVar(&departureUntil, "until", "", "Show departures up to this time, relative to --time (e.g. 18:15, +45m)")
+	departuresCmd.Flags().BoolVar(&blend, "blend", false, "Continue live predictions with timetable departures")
+	departuresCmd.Flags().StringSliceVar(&filter.Lines, "line", nil, "Only show these lines")
+	departuresCmd.Flags().StringSliceVar(&filter.Destinations, "destination", nil, "Only show these destinations")
+	departuresCmd.Flags().StringSliceVar(&filter.Platforms, "platform", nil, "Only show these platforms (e.g. 3,4)")
+	departuresCmd.Flags().StringSliceVar(&filter.Directions, "direction", nil, "Only show these directions (inbound, outbound, or a platform bound such as southbound)")
+	departuresCmd.Flags().StringSliceVar(&filter.Exclude, "exclude", nil, "Hide departures whose line, destination or platform matches")
+	departuresCmd.Flags().BoolVar(&filter.Regex, "regex", false, "Treat filter values as regular expressions")
+	departuresCmd.Flags().StringVar(&groupBy, "group-by", "", "Show a section per platform, line or direction")
+	departuresCmd.Flags().IntVar(&perGroup, "per-group", 0, "Maximum number of departures in each --group-by section")
+	departuresCmd.Flags().StringVar(&departuresStyle, "style", "text", "Text output style: text or board")
+	departuresCmd.Flags().BoolVarP(&watch, "watch", "w", false, "Refresh the departures until interrupted")
+	departuresCmd.Flags().DurationVarP(&watchInterval, "interval", "i", 30*time.Second, "Time between refreshes with --watch")
+	departuresCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show vehicle IDs and current locations")
+	departuresCmd.Flags().BoolVar(&jsonCompat, "json-compat", false, "Use the original (schema version 1) JSON shape")
 	rootCmd.AddCommand(departuresCmd)
 	rootCmd.AddCommand(searchCmd)
 }
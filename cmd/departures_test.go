@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -43,50 +44,69 @@ func TestFilterByMatch(t *testing.T) {
 	}
 }
 
-func TestParseTimeToday(t *testing.T) {
+func TestDepartureQueryBounds(t *testing.T) {
+	now := time.Date(2024, 3, 6, 10, 0, 0, 0, time.UTC)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+
 	tests := []struct {
 		name      string
-		timeStr   string
-		wantHour  int
-		wantMin   int
+		q         departureQuery
+		wantFrom  time.Time
+		wantUntil time.Time
 		wantError bool
 	}{
-		{"valid time", "14:30", 14, 30, false},
-		{"midnight", "00:00", 0, 0, false},
-		{"end of day", "23:59", 23, 59, false},
-		{"single digit hour", "2:30", 2, 30, false},
-		{"invalid format no colon", "1430", 0, 0, true},
-		{"invalid hour", "25:00", 0, 0, true},
-		{"invalid minute", "14:60", 0, 0, true},
-		{"empty string", "", 0, 0, true},
+		{"none", departureQuery{}, time.Time{}, time.Time{}, false},
+		{"time only", departureQuery{Time: "17:30"}, at(6, 17, 30), time.Time{}, false},
+		{"until only", departureQuery{Until: "+45m"}, time.Time{}, at(6, 10, 45), false},
+		{"until clock after time", departureQuery{Time: "17:30", Until: "18:15"}, at(6, 17, 30), at(6, 18, 15), false},
+		{"until relative to time", departureQuery{Time: "17:30", Until: "+45m"}, at(6, 17, 30), at(6, 18, 15), false},
+		{"until on the day of time", departureQuery{Time: "tomorrow 8am", Until: "9am"}, at(7, 8, 0), at(7, 9, 0), false},
+		{"window", departureQuery{Time: "17:30", Window: 45 * time.Minute}, at(6, 17, 30), at(6, 18, 15), false},
+		{"window from now", departureQuery{Window: 90 * time.Minute}, time.Time{}, at(6, 11, 30), false},
+		{"window and until", departureQuery{Until: "18:00", Window: time.Hour}, time.Time{}, time.Time{}, true},
+		{"negative window", departureQuery{Window: -time.Hour}, time.Time{}, time.Time{}, true},
+		{"until before time", departureQuery{Time: "17:30", Until: "17:00"}, time.Time{}, time.Time{}, true},
+		{"bad time", departureQuery{Time: "teatime"}, time.Time{}, time.Time{}, true},
+		{"bad until", departureQuery{Until: "later"}, time.Time{}, time.Time{}, true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseTimeToday(tt.timeStr)
+			from, until, err := tt.q.bounds(now)
 			if tt.wantError {
 				if err == nil {
-					t.Errorf("parseTimeToday(%q) expected error, got nil", tt.timeStr)
+					t.Errorf("bounds() expected error, got %v - %v", from, until)
 				}
 				return
 			}
 			if err != nil {
-				t.Errorf("parseTimeToday(%q) unexpected error: %v", tt.timeStr, err)
-				return
-			}
-			if result.Hour() != tt.wantHour || result.Minute() != tt.wantMin {
-				t.Errorf("parseTimeToday(%q) = %02d:%02d, want %02d:%02d",
-					tt.timeStr, result.Hour(), result.Minute(), tt.wantHour, tt.wantMin)
+				t.Fatalf("bounds() unexpected error: %v", err)
 			}
-			// Verify it's today's date
-			now := time.Now()
-			if result.Year() != now.Year() || result.Month() != now.Month() || result.Day() != now.Day() {
-				t.Errorf("parseTimeToday(%q) date is not today", tt.timeStr)
+			if !from.Equal(tt.wantFrom) || !until.Equal(tt.wantUntil) {
+				t.Errorf("bounds() = %v - %v, want %v - %v", from, until, tt.wantFrom, tt.wantUntil)
 			}
 		})
 	}
 }
 
+func TestFilterUntil(t *testing.T) {
+	base := time.Date(2024, 3, 6, 17, 30, 0, 0, time.UTC)
+	arrivals := []tfl.Arrival{
+		{LineName: "Central", ExpectedArrival: base},
+		{LineName: "District", ExpectedArrival: base.Add(45 * time.Minute)},
+		{LineName: "Northern", ExpectedArrival: base.Add(46 * time.Minute)},
+	}
+
+	if got := filterUntil(arrivals, time.Time{}); len(got) != 3 {
+		t.Errorf("filterUntil(zero) = %d arrivals, want 3", len(got))
+	}
+	if got := filterUntil(arrivals, base.Add(45*time.Minute)); len(got) != 2 {
+		t.Errorf("filterUntil() = %d arrivals, want 2", len(got))
+	}
+}
+
 func TestFilterByTime(t *testing.T) {
 	now := time.Now()
 	baseTime := time.Date(now.Year(), now.Month(), now.Day(), 14, 0, 0, 0, now.Location())
@@ -122,6 +142,50 @@ func TestFilterByTime(t *testing.T) {
 	}
 }
 
+func TestBlendArrivals(t *testing.T) {
+	base := time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC)
+	at := func(mins int) time.Time { return base.Add(time.Duration(mins) * time.Minute) }
+
+	live := []tfl.Arrival{
+		{LineID: "central", Direction: "outbound", DestinationName: "Ealing Broadway", ExpectedArrival: at(5), Source: tfl.SourceRealtime},
+		{LineID: "central", Direction: "outbound", DestinationName: "Ealing Broadway", ExpectedArrival: at(25), Source: tfl.SourceRealtime},
+		{LineID: "central", Direction: "inbound", DestinationName: "Epping", ExpectedArrival: at(10), Source: tfl.SourceRealtime},
+	}
+
+	tests := []struct {
+		name      string
+		scheduled tfl.Arrival
+		kept      bool
+	}{
+		{"covered by live on same direction", tfl.Arrival{LineID: "central", Direction: "outbound", DestinationName: "West Ruislip", ExpectedArrival: at(20)}, false},
+		{"after live horizon", tfl.Arrival{LineID: "central", Direction: "outbound", DestinationName: "West Ruislip", ExpectedArrival: at(35)}, true},
+		{"same trip running late", tfl.Arrival{LineID: "central", Direction: "inbound", DestinationName: "Epping", ExpectedArrival: at(11)}, false},
+		{"other direction after its horizon", tfl.Arrival{LineID: "central", Direction: "inbound", DestinationName: "Hainault", ExpectedArrival: at(15)}, true},
+		{"line without live data before overall horizon", tfl.Arrival{LineID: "district", Direction: "outbound", DestinationName: "Upminster", ExpectedArrival: at(20)}, false},
+		{"line without live data after overall horizon", tfl.Arrival{LineID: "district", Direction: "outbound", DestinationName: "Upminster", ExpectedArrival: at(30)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.scheduled.Source = tfl.SourceTimetable
+			result := blendArrivals(live, []tfl.Arrival{tt.scheduled})
+
+			want := len(live)
+			if tt.kept {
+				want++
+			}
+			if len(result) != want {
+				t.Fatalf("blendArrivals() = %d arrivals, want %d", len(result), want)
+			}
+			for i := 1; i < len(result); i++ {
+				if result[i].ExpectedArrival.Before(result[i-1].ExpectedArrival) {
+					t.Errorf("result not sorted at %d", i)
+				}
+			}
+		})
+	}
+}
+
 func TestSelectBestMatch(t *testing.T) {
 	stops := []tfl.StopPoint{
 		{ID: "1", Name: "Liverpool Street Underground Station"},
@@ -151,6 +215,59 @@ func TestSelectBestMatch(t *testing.T) {
 	}
 }
 
+func TestParseTimetableWindow(t *testing.T) {
+	var tt tfl.TimetableResponse
+	err := json.Unmarshal([]byte(`{
+		"lineId": "central", "lineName": "Central", "direction": "outbound",
+		"timetable": {"routes": [{
+			"stationIntervals": [{"id": "0", "intervals": [{"stopId": "940GZZLUEBY"}]}],
+			"schedules": [
+				{"name": "Monday - Friday", "knownJourneys": [
+					{"hour": "17", "minute": "20", "intervalId": 0},
+					{"hour": "17", "minute": "30", "intervalId": 0},
+					{"hour": "18", "minute": "15", "intervalId": 0},
+					{"hour": "18", "minute": "20", "intervalId": 0},
+					{"hour": "22", "minute": "00", "intervalId": 0}
+				]},
+				{"name": "Saturday", "knownJourneys": [
+					{"hour": "17", "minute": "45", "intervalId": 0}
+				]}
+			]
+		}]}
+	}`), &tt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]string{"940GZZLUEBY": "Ealing Broadway"}
+
+	// Wednesday 6 March 2024.
+	from := time.Date(2024, 3, 6, 17, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name  string
+		until time.Time
+		want  int
+	}{
+		{"until", from.Add(45 * time.Minute), 2},
+		{"default window", time.Time{}, 3},
+		{"long window", from.Add(6 * time.Hour), 4},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseTimetableWithStations(&tt, from, tc.until, names)
+			if len(got) != tc.want {
+				t.Fatalf("parseTimetableWithStations() = %d arrivals, want %d", len(got), tc.want)
+			}
+			for _, a := range got {
+				if a.DestinationName != "Ealing Broadway" || a.ExpectedArrival.Day() != 6 {
+					t.Errorf("unexpected arrival %+v", a)
+				}
+			}
+		})
+	}
+}
+
 func TestScheduleMatchesDay(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -201,3 +318,28 @@ func TestFormatStopID(t *testing.T) {
 		})
 	}
 }
+
+func TestIsStopID(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"940GZZLUKSX", true},
+		{"910GPADTON", true},
+		{"490000173RF", true},
+		{"HUBKGX", true},
+		{"Paddington", false},
+		{"Kings Cross", false},
+		{"940gzzluksx", false},
+		{"HUB", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := isStopID(tt.query); got != tt.want {
+				t.Errorf("isStopID(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,36 +3,98 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"tfl/internal/display"
+	"tfl/internal/tfl"
 )
 
+var plannedOnly bool
+var plannedDays int
+
 var disruptionsCmd = &cobra.Command{
 	Use:     "disruptions",
 	Aliases: []string{"delays"},
 	Short:   "Show service disruptions",
 	Long: `Display current service disruptions across the tube network.
 
+Use --planned to show only planned engineering works. With --planned and
+--format ics, planned works over the next --days days are exported as
+calendar events, one per closure window, taken from the validity periods of
+line statuses that are planned closures or match a PlannedWork disruption.
+
 Examples:
   tfl disruptions
   tfl delays
-  tfl disruptions --format json`,
+  tfl disruptions --planned
+  tfl disruptions --planned --format ics > planned-works.ics
+  tfl disruptions --format json
+  tfl disruptions --format tsv
+  tfl disruptions --format ndjson
+  tfl disruptions --format html`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if outputFormat == "ics" {
+			if !plannedOnly {
+				fmt.Fprintln(os.Stderr, "Error: --format ics exports planned works; use it with --planned")
+				os.Exit(1)
+			}
+			if plannedDays < 1 {
+				fmt.Fprintln(os.Stderr, "Error: --days must be at least 1")
+				os.Exit(1)
+			}
+			now := time.Now()
+			statuses, err := client.GetTubeStatusBetween(now, now.AddDate(0, 0, plannedDays))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			disruptions, err := client.GetDisruptions()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			display.PrintCalendar(display.PlannedWorksEvents(statuses, disruptions))
+			return
+		}
+
 		disruptions, err := client.GetDisruptions()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		if IsJSON() {
+		if plannedOnly {
+			disruptions = filterPlanned(disruptions)
+		}
+
+		switch {
+		case IsJSON():
 			display.PrintDisruptionsJSON(disruptions)
-		} else {
+		case IsNDJSON():
+			display.PrintDisruptionsNDJSON(disruptions, time.Now())
+		case IsTemplate():
+			display.PrintTemplate(outputTemplate, display.NewDisruptionsOutput(disruptions))
+		case IsTable():
+			printTable(display.DisruptionsTable(disruptions))
+		default:
 			display.PrintDisruptions(disruptions)
 		}
 	},
 }
 
+func filterPlanned(disruptions []tfl.Disruption) []tfl.Disruption {
+	var filtered []tfl.Disruption
+	for _, d := range disruptions {
+		if d.Category == "PlannedWork" {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
 func init() {
+	disruptionsCmd.Flags().BoolVar(&plannedOnly, "planned", false, "Show only planned engineering works")
+	disruptionsCmd.Flags().IntVar(&plannedDays, "days", 14, "Days ahead to include in ics output")
 	rootCmd.AddCommand(disruptionsCmd)
 }
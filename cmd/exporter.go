@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tfl/internal/metrics"
+	"tfl/internal/tfl"
+)
+
+var exporterListen string
+var exporterInterval time.Duration
+var exporterStations []string
+
+var exporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "Serve TfL metrics for Prometheus",
+	Long: `Poll TfL in the background and serve the results as Prometheus metrics on
+/metrics.
+
+Exported metrics:
+  tfl_line_status_severity            severity of each current status per line
+                                      (10 is good service)
+  tfl_disruptions                     active disruptions per category
+  tfl_next_departure_minutes          minutes until the next departure per
+                                      station and line (with --station)
+  tfl_api_request_duration_seconds    TfL API request latency per endpoint
+  tfl_api_errors_total                failed TfL API requests per endpoint
+  tfl_api_rate_limited_total          requests rejected by TfL rate limiting
+  tfl_last_poll_timestamp_seconds     time of the last completed poll
+
+Examples:
+  tfl exporter
+  tfl exporter --listen :9300 --interval 30s
+  tfl exporter --station "Liverpool Street" --station 940GZZLUOXC`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if exporterInterval < 10*time.Second {
+			fmt.Fprintln(os.Stderr, "Error: --interval must be at least 10s")
+			os.Exit(1)
+		}
+
+		var stops []tfl.StopPoint
+		for _, query := range exporterStations {
+			stop, err := findStop(query)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			stops = append(stops, stop)
+		}
+
+		registry := metrics.NewRegistry()
+		go func() {
+			for {
+				pollMetrics(registry, stops)
+				time.Sleep(exporterInterval)
+			}
+		}()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+			_ = registry.WriteText(w)
+		})
+
+		fmt.Printf("Serving metrics on http://%s/metrics\n", exporterListen)
+		if err := http.ListenAndServe(exporterListen, mux); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// timed runs an API call and records its latency, failures and rate
+// limiting under the given endpoint label.
+func timed(registry *metrics.Registry, endpoint string, call func() error) error {
+	start := time.Now()
+	err := call()
+	labels := metrics.Labels{"endpoint": endpoint}
+
+	registry.Observe("tfl_api_request_duration_seconds", "TfL API request latency in seconds.", labels, time.Since(start).Seconds())
+	if err != nil {
+		registry.IncCounter("tfl_api_errors_total", "Failed TfL API requests.", labels)
+		if isRateLimited(err) {
+			registry.IncCounter("tfl_api_rate_limited_total", "TfL API requests rejected by rate limiting.", labels)
+		}
+		fmt.Fprintf(os.Stderr, "Error fetching %s: %v\n", endpoint, err)
+	}
+	return err
+}
+
+// rateLimited matches the HTTP status in an API error when TfL has rejected
+// a request as over the rate limit. It must not match a 429 that is part of
+// a stop ID or other text in the message.
+var rateLimited = regexp.MustCompile(`(?i)\b(status|HTTP)[: ]+429\b|too many requests`)
+
+func isRateLimited(err error) bool {
+	return rateLimited.MatchString(err.Error())
+}
+
+func pollMetrics(registry *metrics.Registry, stops []tfl.StopPoint) {
+	var statuses []tfl.LineStatus
+	err := timed(registry, "status", func() (err error) {
+		statuses, err = client.GetTubeStatus()
+		return err
+	})
+	if err == nil {
+		// A line can have several statuses at once, so each gets a series.
+		var samples []metrics.Sample
+		for _, line := range statuses {
+			for _, status := range line.LineStatuses {
+				samples = append(samples, metrics.Sample{
+					Labels: metrics.Labels{"line_id": line.ID, "line": line.Name, "status": status.StatusSeverityDescription},
+					Value:  float64(status.StatusSeverity),
+				})
+			}
+		}
+		registry.ReplaceGauge("tfl_line_status_severity", "Line status severity; 10 is good service.", samples)
+	}
+
+	var disruptions []tfl.Disruption
+	err = timed(registry, "disruptions", func() (err error) {
+		disruptions, err = client.GetDisruptions()
+		return err
+	})
+	if err == nil {
+		counts := make(map[string]int)
+		for _, d := range disruptions {
+			counts[d.Category]++
+		}
+		samples := make([]metrics.Sample, 0, len(counts))
+		for category, count := range counts {
+			samples = append(samples, metrics.Sample{Labels: metrics.Labels{"category": category}, Value: float64(count)})
+		}
+		registry.ReplaceGauge("tfl_disruptions", "Active disruptions by category.", samples)
+	}
+
+	var departures []metrics.Sample
+	for _, stop := range stops {
+		var arrivals []tfl.Arrival
+		err := timed(registry, "arrivals", func() (err error) {
+			arrivals, err = client.GetAllArrivalsAtStop(stop.ID)
+			return err
+		})
+		if err != nil {
+			continue
+		}
+
+		next := make(map[string]tfl.Arrival)
+		for _, a := range arrivals {
+			if n, ok := next[a.LineID]; !ok || a.TimeToStation < n.TimeToStation {
+				next[a.LineID] = a
+			}
+		}
+		for lineID, a := range next {
+			departures = append(departures, metrics.Sample{
+				Labels: metrics.Labels{"station": stop.Name, "stop_id": stop.ID, "line_id": lineID, "line": a.LineName},
+				Value:  float64(a.TimeToStation) / 60,
+			})
+		}
+	}
+	registry.ReplaceGauge("tfl_next_departure_minutes", "Minutes until the next departure.", departures)
+
+	registry.SetGauge("tfl_last_poll_timestamp_seconds", "Unix time of the last completed poll.", nil, float64(time.Now().Unix()))
+}
+
+func init() {
+	exporterCmd.Flags().StringVar(&exporterListen, "listen", ":9300", "Address to serve metrics on")
+	exporterCmd.Flags().DurationVarP(&exporterInterval, "interval", "i", time.Minute, "Time between TfL polls")
+	exporterCmd.Flags().StringArrayVar(&exporterStations, "station", nil, "Station name or stop ID to export next departures for (repeatable)")
+	rootCmd.AddCommand(exporterCmd)
+}
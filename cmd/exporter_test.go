@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsRateLimited(t *testing.T) {
+	tests := []struct {
+		err  string
+		want bool
+	}{
+		{"API returned status 429", true},
+		{"HTTP 429 Too Many Requests", true},
+		{"too many requests", true},
+		{"API returned status 503", false},
+		{"no stations found matching '4900004290S'", false},
+		{"API returned status 4290", false},
+	}
+
+	for _, tt := range tests {
+		if got := isRateLimited(errors.New(tt.err)); got != tt.want {
+			t.Errorf("isRateLimited(%q) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
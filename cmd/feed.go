@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tfl/internal/cache"
+	"tfl/internal/feed"
+	"tfl/internal/notify"
+)
+
+var feedOutput string
+var feedListen string
+var feedStatePath string
+var feedMaxAge time.Duration
+var feedTTL time.Duration
+var feedBaseURL string
+
+var feedCmd = &cobra.Command{
+	Use:   "feed",
+	Short: "Generate an Atom feed of disruptions and status changes",
+	Long: `Generate an Atom feed of current disruptions and line status changes.
+
+Entry IDs are derived from the content, so feed readers only show items that
+are genuinely new. When each disruption or status was first seen is kept in a
+state file, so entries keep their dates between runs.
+
+Without --listen the feed is written once to --output (or stdout), e.g. from
+cron. With --listen it is served over HTTP and refreshed at most every --ttl.
+The feed's self link is --base-url if given; when serving without it, the
+link is built from each request.
+
+Examples:
+  tfl feed > tfl.atom
+  tfl feed --output /var/www/tfl.atom
+  tfl feed --listen 127.0.0.1:8081
+  tfl feed --listen 127.0.0.1:8081 --base-url https://example.com/tfl.atom`,
+	Run: func(cmd *cobra.Command, args []string) {
+		statePath := feedStatePath
+		if statePath == "" {
+			var err error
+			statePath, err = notify.DefaultStatePath("feed")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error locating state file: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		state, err := notify.LoadState(statePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading state file: %v\n", err)
+			os.Exit(1)
+		}
+
+		if feedListen == "" {
+			f, err := pollFeed(state, statePath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			var buf bytes.Buffer
+			if err := f.WithSelf(feedBaseURL).Write(&buf); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if feedOutput == "" {
+				_, _ = os.Stdout.Write(buf.Bytes())
+				return
+			}
+			if err := os.WriteFile(feedOutput, buf.Bytes(), 0o644); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing feed: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		// There is a single feed, so it is cached under one key; only the
+		// self link depends on the request.
+		polled := cache.New(feedTTL)
+		mux := http.NewServeMux()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			v, err := polled.Get("feed", func() (interface{}, error) {
+				return pollFeed(state, statePath)
+			})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			selfURL := feedBaseURL
+			if selfURL == "" {
+				selfURL = "http://" + r.Host + r.URL.Path
+			}
+			var buf bytes.Buffer
+			if err := v.(feed.Feed).WithSelf(selfURL).Write(&buf); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+			_, _ = w.Write(buf.Bytes())
+		})
+
+		server := &http.Server{Addr: feedListen, Handler: mux, ReadHeaderTimeout: serveReadHeaderTimeout}
+		fmt.Printf("Serving feed on http://%s/\n", feedListen)
+		if err := server.ListenAndServe(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// pollFeed polls disruptions and statuses into state, saves it and returns
+// the feed, without a self link.
+func pollFeed(state *notify.State, statePath string) (feed.Feed, error) {
+	now := time.Now()
+
+	disruptions, err := client.GetDisruptions()
+	if err != nil {
+		return feed.Feed{}, fmt.Errorf("fetching disruptions: %w", err)
+	}
+	statuses, err := client.GetTubeStatus()
+	if err != nil {
+		return feed.Feed{}, fmt.Errorf("fetching status: %w", err)
+	}
+
+	state.UpdateDisruptions(disruptions, now)
+	state.Update(statuses, nil, now)
+	if err := state.Save(statePath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving state file: %v\n", err)
+	}
+
+	return feed.Build(state, feedMaxAge, now), nil
+}
+
+func init() {
+	feedCmd.Flags().StringVarP(&feedOutput, "output", "o", "", "File to write the feed to (default stdout)")
+	feedCmd.Flags().StringVar(&feedListen, "listen", "", "Serve the feed over HTTP on this address instead")
+	feedCmd.Flags().StringVar(&feedStatePath, "state", "", "Path of the state file (default in the user cache directory)")
+	feedCmd.Flags().DurationVar(&feedMaxAge, "max-age", 24*time.Hour, "How long lines restored to good service stay in the feed")
+	feedCmd.Flags().DurationVar(&feedTTL, "ttl", time.Minute, "Minimum time between TfL polls when serving")
+	feedCmd.Flags().StringVar(&feedBaseURL, "base-url", "", "Public URL of the feed, used as its self link")
+	rootCmd.AddCommand(feedCmd)
+}
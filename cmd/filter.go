@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"tfl/internal/tfl"
+)
+
+// arrivalFilter holds the structured departure filters. Values for the
+// same field are alternatives; different fields must all match. An
+// arrival matching any Exclude value is dropped. Values are
+// case-insensitive substrings, or regular expressions with Regex.
+type arrivalFilter struct {
+	Lines        []string
+	Destinations []string
+	Platforms    []string
+	Directions   []string
+	Exclude      []string
+	Regex        bool
+}
+
+// platformNumber matches a bare platform number such as "3" or "10a".
+var platformNumber = regexp.MustCompile(`^[0-9]+[a-z]?$`)
+
+// matcher compiles the filter into a predicate, reporting invalid regular
+// expressions.
+func (f arrivalFilter) matcher() (func(tfl.Arrival) bool, error) {
+	lines, err := f.patterns("line", f.Lines, false)
+	if err != nil {
+		return nil, err
+	}
+	destinations, err := f.patterns("destination", f.Destinations, false)
+	if err != nil {
+		return nil, err
+	}
+	// A bare number matches that platform only, so "3" doesn't match
+	// "Platform 13".
+	platforms, err := f.patterns("platform", f.Platforms, true)
+	if err != nil {
+		return nil, err
+	}
+	directions, err := f.patterns("direction", f.Directions, false)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := f.patterns("exclude", f.Exclude, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(a tfl.Arrival) bool {
+		return anyMatch(lines, a.LineID, a.LineName) &&
+			anyMatch(destinations, a.DestinationName) &&
+			anyMatch(platforms, a.PlatformName) &&
+			anyMatch(directions, directionsOf(a)...) &&
+			(len(exclude) == 0 || !anyMatch(exclude, a.LineID, a.LineName, a.DestinationName, a.PlatformName))
+	}, nil
+}
+
+func (f arrivalFilter) patterns(field string, values []string, numbers bool) ([]*regexp.Regexp, error) {
+	var res []*regexp.Regexp
+	for _, v := range values {
+		var expr string
+		switch {
+		case f.Regex:
+			expr = "(?i)" + v
+		case numbers && platformNumber.MatchString(strings.ToLower(v)):
+			expr = `(?i)\b` + regexp.QuoteMeta(v) + `\b`
+		default:
+			expr = "(?i)" + regexp.QuoteMeta(strings.TrimSpace(v))
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %w", field, v, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// directionsOf returns the ways an arrival's direction is described: TfL's
+// inbound or outbound, and the bound in its platform name (the "Westbound"
+// of "Westbound - Platform 1"), either of which may be missing.
+func directionsOf(a tfl.Arrival) []string {
+	dirs := []string{a.Direction}
+	bound, _, _ := strings.Cut(a.PlatformName, " - ")
+	if strings.HasSuffix(strings.ToLower(strings.TrimSpace(bound)), "bound") {
+		dirs = append(dirs, strings.TrimSpace(bound))
+	}
+	return dirs
+}
+
+// splitValues splits each value on commas, as the command-line flags do,
+// dropping empty ones.
+func splitValues(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+// anyMatch reports whether any pattern matches any of the fields. No
+// patterns match everything.
+func anyMatch(patterns []*regexp.Regexp, fields ...string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, re := range patterns {
+		for _, field := range fields {
+			if field != "" && re.MatchString(field) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"tfl/internal/tfl"
+)
+
+func TestArrivalFilter(t *testing.T) {
+	arrivals := []tfl.Arrival{
+		{LineID: "piccadilly", LineName: "Piccadilly", DestinationName: "Uxbridge", PlatformName: "Westbound - Platform 3", Direction: "outbound"},
+		{LineID: "piccadilly", LineName: "Piccadilly", DestinationName: "Heathrow Terminal 5", PlatformName: "Westbound - Platform 3", Direction: "outbound"},
+		{LineID: "piccadilly", LineName: "Piccadilly", DestinationName: "Cockfosters", PlatformName: "Eastbound - Platform 4", Direction: "inbound"},
+		{LineID: "victoria", LineName: "Victoria", DestinationName: "Brixton", PlatformName: "Southbound - Platform 13", Direction: "outbound"},
+		{LineID: "northern", LineName: "Northern", DestinationName: "Morden", PlatformName: "Northbound - Platform 1"},
+		{LineID: "jubilee", LineName: "Jubilee", DestinationName: "Stratford", PlatformName: "Southbound - Platform 2", Direction: "inbound"},
+	}
+
+	tests := []struct {
+		name   string
+		filter arrivalFilter
+		want   []string
+	}{
+		{"no filters", arrivalFilter{}, []string{"Uxbridge", "Heathrow Terminal 5", "Cockfosters", "Brixton", "Morden", "Stratford"}},
+		{"line but not destination", arrivalFilter{Lines: []string{"piccadilly"}, Exclude: []string{"uxbridge"}}, []string{"Heathrow Terminal 5", "Cockfosters"}},
+		{"platform numbers", arrivalFilter{Platforms: []string{"3", "4"}}, []string{"Uxbridge", "Heathrow Terminal 5", "Cockfosters"}},
+		{"platform number is exact", arrivalFilter{Platforms: []string{"1"}}, []string{"Morden"}},
+		{"platform text", arrivalFilter{Platforms: []string{"southbound"}}, []string{"Brixton", "Stratford"}},
+		{"direction field", arrivalFilter{Directions: []string{"inbound"}}, []string{"Cockfosters", "Stratford"}},
+		{"direction from platform", arrivalFilter{Directions: []string{"northbound"}}, []string{"Morden"}},
+		{"platform bound alongside direction", arrivalFilter{Directions: []string{"westbound"}}, []string{"Uxbridge", "Heathrow Terminal 5"}},
+		{"jubilee southbound", arrivalFilter{Lines: []string{"jubilee"}, Directions: []string{"southbound"}}, []string{"Stratford"}},
+		{"platform number is not a direction", arrivalFilter{Directions: []string{"platform"}}, nil},
+		{"values are alternatives", arrivalFilter{Destinations: []string{"brixton", "morden"}}, []string{"Brixton", "Morden"}},
+		{"fields are combined", arrivalFilter{Lines: []string{"piccadilly"}, Directions: []string{"outbound"}}, []string{"Uxbridge", "Heathrow Terminal 5"}},
+		{"exclude several", arrivalFilter{Exclude: []string{"piccadilly", "northern", "jubilee"}}, []string{"Brixton"}},
+		{"line by name", arrivalFilter{Lines: []string{"Victoria"}}, []string{"Brixton"}},
+		{"regex", arrivalFilter{Destinations: []string{"^(morden|brixton)$"}, Regex: true}, []string{"Brixton", "Morden"}},
+		{"regex is not substring mode", arrivalFilter{Destinations: []string{"^heathrow"}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keep, err := tt.filter.matcher()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, a := range arrivals {
+				if keep(a) {
+					got = append(got, a.DestinationName)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("kept %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("kept %q, want %q", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestArrivalFilterInvalidRegex(t *testing.T) {
+	_, err := arrivalFilter{Lines: []string{"(piccadilly"}, Regex: true}.matcher()
+	if err == nil {
+		t.Error("expected error for invalid regex")
+	}
+}
+
+func TestSplitValues(t *testing.T) {
+	got := splitValues([]string{"3,4", " 5 ", "", "a,,b"})
+	want := []string{"3", "4", "5", "a", "b"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("splitValues() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"tfl/internal/display"
+)
+
+var lineDirection string
+
+var lineCmd = &cobra.Command{
+	Use:   "line <line-name>",
+	Short: "Show the stations on a line",
+	Long: `Show the ordered stations on a line as a strip map. Stations shared by
+several routes are shown once, with each branch splitting off and rejoining
+the trunk where it does (e.g. the Northern line via Bank or Charing Cross).
+
+Interchanges with other lines are marked with <> and the connecting lines.
+Line names are matched case-insensitively, e.g. "hammersmith & city" or
+"Elizabeth line".
+
+Examples:
+  tfl line northern
+  tfl line central --direction inbound
+  tfl line "hammersmith & city"
+  tfl line mildmay
+  tfl line "ifs cloud cable car"
+  tfl line elizabeth --format json
+  tfl line victoria --format markdown`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{formatsAnnotation: "text,json,csv,tsv,markdown,html,template"},
+	Run: func(cmd *cobra.Command, args []string) {
+		direction := strings.ToLower(lineDirection)
+		if direction != "inbound" && direction != "outbound" {
+			fmt.Fprintln(os.Stderr, "Error: --direction must be inbound or outbound")
+			os.Exit(1)
+		}
+
+		seq, err := client.GetRouteSequence(lineIDFromName(args[0]), direction)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		switch {
+		case IsJSON():
+			display.PrintRouteSequenceJSON(seq)
+		case IsTemplate():
+			display.PrintTemplate(outputTemplate, display.NewRouteOutput(seq))
+		case IsTable():
+			printTable(display.RouteSequenceTable(seq))
+		default:
+			display.PrintRouteSequence(seq)
+		}
+	},
+}
+
+// lineIDFromName turns a human line name such as "Hammersmith & City" or
+// "Elizabeth line" into the TfL line ID ("hammersmith-city", "elizabeth").
+// Names in the line registry, such as "IFS Cloud Cable Car", map to their
+// registered ID.
+func lineIDFromName(name string) string {
+	if l, ok := display.LookupLineByName(name); ok {
+		return l.ID
+	}
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, "&", " ")
+	name = strings.ReplaceAll(name, " and ", " ")
+	name = strings.TrimSuffix(strings.TrimSpace(name), " line")
+	return strings.Join(strings.Fields(name), "-")
+}
+
+func init() {
+	lineCmd.Flags().StringVarP(&lineDirection, "direction", "d", "outbound", "Direction of travel: inbound or outbound")
+	rootCmd.AddCommand(lineCmd)
+}
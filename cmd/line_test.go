@@ -0,0 +1,30 @@
+package cmd
+
+import "testing"
+
+func TestLineIDFromName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"northern", "northern"},
+		{"Central", "central"},
+		{"Hammersmith & City", "hammersmith-city"},
+		{"hammersmith and city", "hammersmith-city"},
+		{"Waterloo & City", "waterloo-city"},
+		{"Elizabeth line", "elizabeth"},
+		{"  Jubilee  ", "jubilee"},
+		{"london-overground", "london-overground"},
+		{"Mildmay", "mildmay"},
+		{"IFS Cloud Cable Car", "london-cable-car"},
+		{"DLR", "dlr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineIDFromName(tt.name); got != tt.want {
+				t.Errorf("lineIDFromName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"tfl/internal/display"
+)
+
+var nearbyLat float64
+var nearbyLon float64
+var nearbyRadius int
+var nearbyModes string
+
+var nearbyCmd = &cobra.Command{
+	Use:   "nearby",
+	Short: "List stops near a location",
+	Long: `List stops within a radius of the given coordinates, closest first.
+
+Distances are straight-line; walking times are a rough estimate. The printed
+stop IDs can be passed straight to the departures command.
+
+Examples:
+  tfl nearby --lat 51.53 --lon -0.12
+  tfl nearby --lat 51.53 --lon -0.12 --radius 400
+  tfl nearby --lat 51.53 --lon -0.12 --mode tube,bus
+  tfl nearby --lat 51.53 --lon -0.12 --format json
+  tfl nearby --lat 51.53 --lon -0.12 --format csv`,
+	Annotations: map[string]string{formatsAnnotation: "text,json,csv,tsv,markdown,html,template"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !cmd.Flags().Changed("lat") || !cmd.Flags().Changed("lon") {
+			fmt.Fprintln(os.Stderr, "Error: both --lat and --lon are required")
+			os.Exit(1)
+		}
+		if err := checkCoordinates(nearbyLat, nearbyLon); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if nearbyRadius <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: --radius must be greater than zero")
+			os.Exit(1)
+		}
+
+		var modes []string
+		for _, m := range strings.Split(nearbyModes, ",") {
+			if m = strings.TrimSpace(strings.ToLower(m)); m != "" {
+				modes = append(modes, m)
+			}
+		}
+
+		stops, err := client.GetStopPointsNearby(nearbyLat, nearbyLon, nearbyRadius, modes)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		switch {
+		case IsJSON():
+			display.PrintNearbyStopsJSON(stops, nearbyLat, nearbyLon, nearbyRadius)
+		case IsTemplate():
+			display.PrintTemplate(outputTemplate, display.NewNearbyOutput(stops, nearbyLat, nearbyLon, nearbyRadius))
+		case IsTable():
+			printTable(display.NearbyStopsTable(stops, nearbyLat, nearbyLon))
+		default:
+			display.PrintNearbyStops(stops, nearbyLat, nearbyLon, nearbyRadius)
+		}
+	},
+}
+
+// checkCoordinates rejects a latitude or longitude that is off the globe
+// (or not a number) before it reaches the API.
+func checkCoordinates(lat, lon float64) error {
+	if !(lat >= -90 && lat <= 90) {
+		return fmt.Errorf("--lat must be between -90 and 90, got %g", lat)
+	}
+	if !(lon >= -180 && lon <= 180) {
+		return fmt.Errorf("--lon must be between -180 and 180, got %g", lon)
+	}
+	return nil
+}
+
+func init() {
+	nearbyCmd.Flags().Float64Var(&nearbyLat, "lat", 0, "Latitude of the search origin")
+	nearbyCmd.Flags().Float64Var(&nearbyLon, "lon", 0, "Longitude of the search origin")
+	nearbyCmd.Flags().IntVar(&nearbyRadius, "radius", 800, "Search radius in metres")
+	nearbyCmd.Flags().StringVar(&nearbyModes, "mode", "", "Comma-separated modes to include (e.g. tube,bus)")
+	rootCmd.AddCommand(nearbyCmd)
+}
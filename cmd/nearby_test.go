@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCheckCoordinates(t *testing.T) {
+	tests := []struct {
+		lat, lon float64
+		wantErr  bool
+	}{
+		{51.53, -0.12, false},
+		{90, 180, false},
+		{-90, -180, false},
+		{200, -0.12, true},
+		{-90.5, 0, true},
+		{51.53, 180.1, true},
+		{51.53, -360, true},
+		{math.NaN(), 0, true},
+		{0, math.NaN(), true},
+	}
+
+	for _, tt := range tests {
+		if err := checkCoordinates(tt.lat, tt.lon); (err != nil) != tt.wantErr {
+			t.Errorf("checkCoordinates(%v, %v) = %v, want error %v", tt.lat, tt.lon, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tfl/internal/notify"
+)
+
+var notifyLines string
+var notifyInterval time.Duration
+var notifyExec string
+var notifyDesktopCmd string
+var notifyStatePath string
+var notifyOnce bool
+var notifyDisruptions bool
+var notifyWebhooks []string
+var notifyWebhookSecret string
+var notifyWebhookRetries int
+var notifyDryRun bool
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Notify when line statuses or disruptions change",
+	Long: `Poll line statuses and fire a notification whenever a line's severity or
+reason changes. With --disruptions, disruptions that start or clear are
+reported too. The last known state is persisted, so restarts do not repeat
+notifications. Run it in the background or under a service manager.
+
+Each change is logged to stdout and passed to the configured sinks:
+  --exec        runs a shell command with TFL_LINE, TFL_LINE_ID,
+                TFL_OLD_STATUS, TFL_NEW_STATUS, TFL_OLD_SEVERITY,
+                TFL_NEW_SEVERITY, TFL_REASON, TFL_TITLE and TFL_MESSAGE set
+  --notify-cmd  runs a desktop notifier with the title and message appended
+                as arguments (e.g. notify-send)
+  --webhook     POSTs a versioned JSON payload to the URL, retrying on
+                failure; with --webhook-secret (or TFL_WEBHOOK_SECRET) the
+                body is signed with HMAC-SHA256 in the X-TfL-Signature header
+
+--dry-run prints the webhook payloads instead, runs no other sinks and leaves
+the saved state alone, so the same changes are reported on the next run.
+
+Examples:
+  tfl notify --lines piccadilly,victoria
+  tfl notify --lines central --notify-cmd notify-send
+  tfl notify --exec 'echo "$TFL_TITLE" >> ~/tfl.log' --interval 2m
+  tfl notify --disruptions --webhook https://chat.example.com/hooks/tfl
+  tfl notify --webhook https://chat.example.com/hooks/tfl --dry-run --once
+  tfl notify --once`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if notifyInterval < 10*time.Second {
+			fmt.Fprintln(os.Stderr, "Error: --interval must be at least 10s")
+			os.Exit(1)
+		}
+
+		statePath := notifyStatePath
+		if statePath == "" {
+			var err error
+			statePath, err = notify.DefaultStatePath("notify")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error locating state file: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		state, err := notify.LoadState(statePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading state file: %v\n", err)
+			os.Exit(1)
+		}
+
+		var lineIDs []string
+		for _, name := range strings.Split(notifyLines, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				lineIDs = append(lineIDs, lineIDFromName(name))
+			}
+		}
+
+		// A dry run only previews webhook payloads: other sinks are not run
+		// and the state is not saved, so the changes are reported again.
+		var sinks []notify.Sink
+		if notifyExec != "" && !notifyDryRun {
+			sinks = append(sinks, notify.CommandSink{Command: notifyExec})
+		}
+		if fields := strings.Fields(notifyDesktopCmd); len(fields) > 0 && !notifyDryRun {
+			sinks = append(sinks, notify.DesktopSink{Program: fields[0], Args: fields[1:]})
+		}
+
+		secret := notifyWebhookSecret
+		if secret == "" {
+			secret = os.Getenv("TFL_WEBHOOK_SECRET")
+		}
+		for _, url := range notifyWebhooks {
+			sinks = append(sinks, notify.WebhookSink{
+				URL:     url,
+				Secret:  secret,
+				Retries: notifyWebhookRetries,
+				Backoff: time.Second,
+				DryRun:  notifyDryRun,
+				Out:     os.Stdout,
+			})
+		}
+
+		for {
+			poll(state, statePath, lineIDs, sinks)
+			if notifyOnce {
+				return
+			}
+			time.Sleep(notifyInterval)
+		}
+	},
+}
+
+// poll fetches statuses (and disruptions, if enabled) once and notifies
+// every sink of changes. Errors are reported but not fatal so a transient
+// API failure does not stop the daemon.
+func poll(state *notify.State, statePath string, lineIDs []string, sinks []notify.Sink) {
+	now := time.Now()
+	var changes []notify.Change
+
+	statuses, err := client.GetTubeStatus()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching status: %v\n", err)
+	} else {
+		changes = append(changes, state.Update(statuses, lineIDs, now)...)
+	}
+
+	if notifyDisruptions {
+		disruptions, err := client.GetDisruptions()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching disruptions: %v\n", err)
+		} else {
+			changes = append(changes, state.UpdateDisruptions(disruptions, now)...)
+		}
+	}
+
+	for _, change := range changes {
+		if change.Kind == notify.KindLineStatus {
+			fmt.Printf("[%s] %s: %s -> %s\n", now.Format("15:04:05"), change.New.Line, change.Old.Status, change.New.Status)
+		} else {
+			fmt.Printf("[%s] %s\n", now.Format("15:04:05"), notify.Title(change))
+		}
+		for _, sink := range sinks {
+			if err := sink.Notify(change); err != nil {
+				fmt.Fprintf(os.Stderr, "Error sending notification: %v\n", err)
+			}
+		}
+	}
+
+	if notifyDryRun {
+		return
+	}
+	if err := state.Save(statePath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving state file: %v\n", err)
+	}
+}
+
+func init() {
+	notifyCmd.Flags().StringVar(&notifyLines, "lines", "", "Comma-separated lines to watch (default all)")
+	notifyCmd.Flags().DurationVarP(&notifyInterval, "interval", "i", time.Minute, "Time between status polls")
+	notifyCmd.Flags().StringVar(&notifyExec, "exec", "", "Shell command to run for each change")
+	notifyCmd.Flags().StringVar(&notifyDesktopCmd, "notify-cmd", "", "Desktop notifier to run with title and message (e.g. notify-send)")
+	notifyCmd.Flags().StringVar(&notifyStatePath, "state", "", "Path of the state file (default in the user cache directory)")
+	notifyCmd.Flags().BoolVar(&notifyOnce, "once", false, "Poll once and exit")
+	notifyCmd.Flags().BoolVar(&notifyDisruptions, "disruptions", false, "Also report disruptions that start or clear")
+	notifyCmd.Flags().StringArrayVar(&notifyWebhooks, "webhook", nil, "URL to POST changes to (repeatable)")
+	notifyCmd.Flags().StringVar(&notifyWebhookSecret, "webhook-secret", "", "Secret for signing webhook payloads (or set TFL_WEBHOOK_SECRET)")
+	notifyCmd.Flags().IntVar(&notifyWebhookRetries, "webhook-retries", 3, "Retries for failed webhook deliveries")
+	notifyCmd.Flags().BoolVar(&notifyDryRun, "dry-run", false, "Print webhook payloads instead of sending them; run no other sinks and keep the state unchanged")
+	rootCmd.AddCommand(notifyCmd)
+}
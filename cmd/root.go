@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
 
 	"github.com/spf13/cobra"
 
+	"tfl/internal/display"
 	"tfl/internal/tfl"
 )
 
@@ -12,8 +17,24 @@ var (
 	appKey       string
 	client       *tfl.Client
 	outputFormat string
+	columns      []string
+	templateFile string
+	colorMode    string
+	themeName    string
+
+	outputTemplate *template.Template
 )
 
+// templatePrefix introduces an inline template: --format 'template={{...}}'.
+const templatePrefix = "template="
+
+var formats = []string{"text", "json", "ndjson", "csv", "tsv", "markdown", "html", "ics"}
+
+// formatsAnnotation is set on commands that support only some output
+// formats, to the comma-separated list they do support. "template" stands
+// for --format template=... and --template-file.
+const formatsAnnotation = "formats"
+
 var rootCmd = &cobra.Command{
 	Use:   "tfl",
 	Short: "Transport for London CLI",
@@ -27,8 +48,33 @@ Examples:
   tfl disruptions                         Show current service disruptions
   tfl departures "Liverpool Street"       Show departures from a station
   tfl departures Paddington -m Central    Filter by line or destination
-  tfl search "King's Cross"               Search for stations`,
+  tfl search "King's Cross"               Search for stations
+  tfl nearby --lat 51.53 --lon -0.12      List stops near a location`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if templateFile != "" && cmd.Flags().Changed("format") {
+			fmt.Fprintln(os.Stderr, "Error: --template-file and --format can't be used together")
+			os.Exit(1)
+		}
+		if err := loadTemplate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if outputTemplate == nil && !isValidFormat(outputFormat) {
+			fmt.Fprintf(os.Stderr, "Error: unknown format '%s' (use %s, or template=...)\n", outputFormat, strings.Join(formats, ", "))
+			os.Exit(1)
+		}
+		if err := checkCommandFormat(cmd); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if outputFormat == "ics" && cmd != disruptionsCmd {
+			fmt.Fprintln(os.Stderr, "Error: ics format is only supported by the disruptions command")
+			os.Exit(1)
+		}
+		if err := setupColor(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 		if appKey == "" {
 			appKey = os.Getenv("TFL_APP_KEY")
 		}
@@ -42,10 +88,138 @@ func Execute() error {
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&appKey, "key", "", "TfL API key (or set TFL_APP_KEY env var)")
-	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "text", "Output format: text or json")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "text", "Output format: text, json, ndjson, csv, tsv, markdown, html, ics or template=<go-template>")
+	rootCmd.PersistentFlags().StringVar(&templateFile, "template-file", "", "Go template file to render output with")
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "Colour output: auto, always or never (NO_COLOR is honoured in auto)")
+	rootCmd.PersistentFlags().StringVar(&themeName, "theme", "", "Colour theme: default, colorblind or a JSON theme file (or set TFL_THEME)")
+	rootCmd.PersistentFlags().StringSliceVar(&columns, "columns", nil, "Columns to include in table output, in order (e.g. line,destination)")
 	rootCmd.SetHelpCommand(&cobra.Command{Hidden: true})
 }
 
 func IsJSON() bool {
 	return outputFormat == "json"
 }
+
+// IsNDJSON reports whether output should be one JSON object per line.
+func IsNDJSON() bool {
+	return outputFormat == "ndjson"
+}
+
+// IsTable reports whether output should be a table (csv, tsv, markdown
+// or html).
+func IsTable() bool {
+	switch outputFormat {
+	case "csv", "tsv", "markdown", "html":
+		return true
+	}
+	return false
+}
+
+func isValidFormat(format string) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCommandFormat rejects an output format that cmd has no form for,
+// rather than letting it fall back to text.
+func checkCommandFormat(cmd *cobra.Command) error {
+	supported, ok := cmd.Annotations[formatsAnnotation]
+	if !ok {
+		return nil
+	}
+	format := outputFormat
+	if IsTemplate() {
+		format = "template"
+	}
+	for _, f := range strings.Split(supported, ",") {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s format is not supported by the %s command (use %s)", format, cmd.Name(), strings.ReplaceAll(supported, ",", ", "))
+}
+
+func printTable(t display.Table) {
+	if err := display.PrintTable(t, outputFormat, columns); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// IsTemplate reports whether output should be rendered with a user template.
+func IsTemplate() bool {
+	return outputTemplate != nil
+}
+
+// loadTemplate parses the template given inline in --format or through
+// --template-file, if any.
+func loadTemplate() error {
+	var text string
+	switch {
+	case templateFile != "":
+		data, err := os.ReadFile(templateFile)
+		if err != nil {
+			return fmt.Errorf("reading template: %w", err)
+		}
+		text = string(data)
+	case strings.HasPrefix(outputFormat, templatePrefix):
+		text = strings.TrimPrefix(outputFormat, templatePrefix)
+	default:
+		return nil
+	}
+
+	tmpl, err := display.ParseTemplate(text)
+	if err != nil {
+		return fmt.Errorf("parsing template: %w", err)
+	}
+	outputTemplate = tmpl
+	return nil
+}
+
+// setupColor applies --color and the colour theme. Without --theme or
+// TFL_THEME, a theme at <config dir>/tfl/theme.json is used if present.
+// Only a theme named with --theme is fatal if it can't be used; otherwise
+// a warning is printed and the built-in colours are kept, so a broken
+// config file doesn't stop every command.
+func setupColor() error {
+	profile, err := display.DetectColorProfile(colorMode, display.IsTerminal(os.Stdout), os.Getenv)
+	if err != nil {
+		return err
+	}
+	display.SetColorProfile(profile)
+
+	name := themeName
+	if name == "" {
+		name = os.Getenv("TFL_THEME")
+	}
+	if name == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return nil
+		}
+		path := filepath.Join(dir, "tfl", "theme.json")
+		if _, err := os.Stat(path); err != nil {
+			return nil
+		}
+		name = path
+	}
+
+	err = applyTheme(name)
+	if err != nil && themeName == "" {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring theme %s: %v\n", name, err)
+		return nil
+	}
+	return err
+}
+
+func applyTheme(name string) error {
+	theme, err := display.LoadTheme(name)
+	if err != nil {
+		return err
+	}
+	return display.ApplyTheme(theme)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+	"text/template"
+)
+
+func TestCheckCommandFormat(t *testing.T) {
+	defer func(format string, tmpl *template.Template) {
+		outputFormat, outputTemplate = format, tmpl
+	}(outputFormat, outputTemplate)
+
+	tests := []struct {
+		name     string
+		format   string
+		template bool
+		wantErr  bool
+	}{
+		{"nearby", "csv", false, false},
+		{"nearby", "ndjson", false, true},
+		{"line", "markdown", false, false},
+		{"line", "template={{.line}}", true, false},
+		{"track", "html", false, false},
+		{"track", "ndjson", false, true},
+		{"check", "json", false, false},
+		{"check", "csv", false, true},
+		{"check", "template={{.ok}}", true, true},
+		{"status", "ndjson", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.format, func(t *testing.T) {
+			cmd, _, err := rootCmd.Find([]string{tt.name})
+			if err != nil {
+				t.Fatal(err)
+			}
+			outputFormat, outputTemplate = tt.format, nil
+			if tt.template {
+				outputTemplate = template.New("")
+			}
+			err = checkCommandFormat(cmd)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkCommandFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
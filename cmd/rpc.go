@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tfl/internal/display"
+	"tfl/internal/jsonrpc"
+)
+
+var rpcCmd = &cobra.Command{
+	Use:   "rpc",
+	Short: "Answer JSON-RPC 2.0 requests on stdin",
+	Long: `Read JSON-RPC 2.0 requests from stdin and write one response per line to
+stdout, for editor plugins and tools that want to query TfL without starting
+a process per call. Results use the same shapes as --format json.
+
+Methods:
+  status                                         line statuses
+  disruptions                                    current disruptions
+  departures  {station, match?, limit?, time?,   departures from a station
+               line?, destination?, platform?,
+               direction?, exclude?, regex?}
+  search      {query}                            stations matching a name
+  stationInfo {station}                          lines and child stops of a station
+
+Examples:
+  echo '{"jsonrpc":"2.0","id":1,"method":"status"}' | tfl rpc
+  echo '{"jsonrpc":"2.0","id":2,"method":"departures","params":{"station":"Paddington","limit":3}}' | tfl rpc`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := jsonrpc.Serve(os.Stdin, os.Stdout, handleRPC); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+type rpcStationParams struct {
+	Station string `json:"station"`
+	Match   string `json:"match"`
+	Limit   int    `json:"limit"`
+	Time    string `json:"time"`
+	Until   string `json:"until"`
+	Window  string `json:"window"`
+	Blend   bool   `json:"blend"`
+
+	Lines        []string `json:"line"`
+	Destinations []string `json:"destination"`
+	Platforms    []string `json:"platform"`
+	Directions   []string `json:"direction"`
+	Exclude      []string `json:"exclude"`
+	Regex        bool     `json:"regex"`
+}
+
+type rpcSearchParams struct {
+	Query string `json:"query"`
+}
+
+func handleRPC(method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case "status":
+		statuses, err := client.GetTubeStatus()
+		if err != nil {
+			return nil, err
+		}
+		return display.NewStatusOutput(statuses), nil
+
+	case "disruptions":
+		disruptions, err := client.GetDisruptions()
+		if err != nil {
+			return nil, err
+		}
+		return display.NewDisruptionsOutput(disruptions), nil
+
+	case "search":
+		var p rpcSearchParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Query == "" {
+			return nil, invalidParams("query is required")
+		}
+		stops, err := client.SearchStopPoints(p.Query)
+		if err != nil {
+			return nil, err
+		}
+		return display.NewStopPointsOutput(stops), nil
+
+	case "departures":
+		var p rpcStationParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Station == "" {
+			return nil, invalidParams("station is required")
+		}
+		if p.Limit < 0 {
+			return nil, invalidParams("limit must not be negative")
+		}
+		q := departureQuery{Match: p.Match, Time: p.Time, Until: p.Until, Limit: p.Limit, Blend: p.Blend}
+		q.Filter = arrivalFilter{
+			Lines:        p.Lines,
+			Destinations: p.Destinations,
+			Platforms:    p.Platforms,
+			Directions:   p.Directions,
+			Exclude:      p.Exclude,
+			Regex:        p.Regex,
+		}
+		if _, err := q.Filter.matcher(); err != nil {
+			return nil, invalidParams(err.Error())
+		}
+		if p.Window != "" {
+			d, err := time.ParseDuration(p.Window)
+			if err != nil {
+				return nil, invalidParams("window must be a duration such as 90m")
+			}
+			q.Window = d
+		}
+		if _, _, err := q.bounds(time.Now()); err != nil {
+			return nil, invalidParams(err.Error())
+		}
+		found, err := resolveStop(p.Station)
+		if err != nil {
+			return nil, err
+		}
+		result, err := getDepartures(found.Stop.ID, q)
+		if err != nil {
+			return nil, err
+		}
+		return display.NewDeparturesOutput(result.Arrivals, departuresContext(p.Station, found, result)), nil
+
+	case "stationInfo":
+		var p rpcStationParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Station == "" {
+			return nil, invalidParams("station is required")
+		}
+		stop, err := findStop(p.Station)
+		if err != nil {
+			return nil, err
+		}
+		detail, err := client.GetStopPointDetails(stop.ID)
+		if err != nil {
+			return nil, err
+		}
+		return display.NewStationInfoOutput(detail), nil
+	}
+
+	return nil, &jsonrpc.Error{Code: jsonrpc.CodeMethodNotFound, Message: "method not found: " + method}
+}
+
+func decodeParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return invalidParams(err.Error())
+	}
+	return nil
+}
+
+func invalidParams(msg string) error {
+	return &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: msg}
+}
+
+func init() {
+	rootCmd.AddCommand(rpcCmd)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"tfl/internal/display"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [output]",
+	Short: "Print the JSON Schema for an output",
+	Long: `Print the JSON Schema describing the --format json output of a command.
+Schemas ending in -ndjson describe one line of --format ndjson output.
+Without an argument, lists the available schemas.
+
+Examples:
+  tfl schema
+  tfl schema departures
+  tfl schema departures-v1
+  tfl schema departures-ndjson`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			for _, name := range display.SchemaNames() {
+				fmt.Println(name)
+			}
+			return
+		}
+		data, err := display.Schema(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if _, err := os.Stdout.Write(data); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tfl/internal/cache"
+	"tfl/internal/display"
+)
+
+var serveListen string
+var serveTTL time.Duration
+
+// stopTTL is how long resolved station names are cached; stations are far
+// more stable than departures.
+const stopTTL = time.Hour
+
+// serveReadHeaderTimeout bounds how long a client may take to send request
+// headers, so slow clients can't hold connections open indefinitely.
+const serveReadHeaderTimeout = 10 * time.Second
+
+// The station and departure lookups behind /departures; tests replace them.
+var (
+	serveResolveStop = resolveStop
+	serveDepartures  = getDepartures
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve TfL data over HTTP",
+	Long: `Serve the CLI's data as JSON over HTTP, in the same shapes as --format json.
+
+Responses are cached for --ttl and concurrent requests for the same data
+share a single TfL call, so many dashboards can poll without multiplying
+API usage.
+
+Endpoints:
+  GET /status
+  GET /disruptions
+  GET /departures?station=<name-or-id>&match=&limit=&time=HH:MM
+      &line=&destination=&platform=&direction=&exclude=&regex=true
+  GET /search?q=<name>
+
+Examples:
+  tfl serve
+  tfl serve --listen 127.0.0.1:8080 --ttl 1m`,
+	Run: func(cmd *cobra.Command, args []string) {
+		responses := cache.New(serveTTL)
+		stops := cache.New(stopTTL)
+		go func() {
+			for range time.Tick(time.Minute) {
+				responses.Prune()
+				stops.Prune()
+			}
+		}()
+
+		server := &http.Server{
+			Addr:              serveListen,
+			Handler:           newServeMux(responses, stops),
+			ReadHeaderTimeout: serveReadHeaderTimeout,
+		}
+		fmt.Printf("Serving on http://%s\n", serveListen)
+		if err := server.ListenAndServe(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// newServeMux returns the HTTP handlers, caching TfL responses in
+// responses and resolved station names in stops.
+func newServeMux(responses, stops *cache.Cache) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		serveCached(w, responses, "status", func() (interface{}, error) {
+			statuses, err := client.GetTubeStatus()
+			if err != nil {
+				return nil, err
+			}
+			return display.NewStatusOutput(statuses), nil
+		})
+	})
+	mux.HandleFunc("/disruptions", func(w http.ResponseWriter, r *http.Request) {
+		serveCached(w, responses, "disruptions", func() (interface{}, error) {
+			disruptions, err := client.GetDisruptions()
+			if err != nil {
+				return nil, err
+			}
+			return display.NewDisruptionsOutput(disruptions), nil
+		})
+	})
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		if q == "" {
+			writeError(w, http.StatusBadRequest, "missing q parameter")
+			return
+		}
+		serveCached(w, responses, "search\x00"+q, func() (interface{}, error) {
+			found, err := client.SearchStopPoints(q)
+			if err != nil {
+				return nil, err
+			}
+			return display.NewStopPointsOutput(found), nil
+		})
+	})
+	mux.HandleFunc("/departures", func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		station := params.Get("station")
+		if station == "" {
+			writeError(w, http.StatusBadRequest, "missing station parameter")
+			return
+		}
+
+		q := departureQuery{Match: params.Get("match"), Time: params.Get("time")}
+		if l := params.Get("limit"); l != "" {
+			n, err := strconv.Atoi(l)
+			if err != nil || n < 0 {
+				writeError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+				return
+			}
+			q.Limit = n
+		}
+		if b := params.Get("blend"); b != "" {
+			v, err := strconv.ParseBool(b)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "blend must be true or false")
+				return
+			}
+			q.Blend = v
+		}
+		q.Until = params.Get("until")
+		q.Filter = arrivalFilter{
+			Lines:        splitValues(params["line"]),
+			Destinations: splitValues(params["destination"]),
+			Platforms:    splitValues(params["platform"]),
+			Directions:   splitValues(params["direction"]),
+			Exclude:      splitValues(params["exclude"]),
+		}
+		if re := params.Get("regex"); re != "" {
+			v, err := strconv.ParseBool(re)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "regex must be true or false")
+				return
+			}
+			q.Filter.Regex = v
+		}
+		if _, err := q.Filter.matcher(); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if win := params.Get("window"); win != "" {
+			d, err := time.ParseDuration(win)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "window must be a duration such as 90m")
+				return
+			}
+			q.Window = d
+		}
+		if _, _, err := q.bounds(time.Now()); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		v, err := stops.Get(station, func() (interface{}, error) {
+			return serveResolveStop(station)
+		})
+		if err != nil {
+			status := http.StatusBadGateway
+			if errors.Is(err, errNoStations) {
+				status = http.StatusNotFound
+			}
+			writeError(w, status, err.Error())
+			return
+		}
+		found := v.(stopMatch)
+
+		key := fmt.Sprintf("departures\x00%s\x00%+v", found.Stop.ID, q)
+		serveCached(w, responses, key, func() (interface{}, error) {
+			result, err := serveDepartures(found.Stop.ID, q)
+			if err != nil {
+				return nil, err
+			}
+			return display.NewDeparturesOutput(result.Arrivals, departuresContext(station, found, result)), nil
+		})
+	})
+	return mux
+}
+
+func serveCached(w http.ResponseWriter, c *cache.Cache, key string, fetch func() (interface{}, error)) {
+	v, err := c.Get(key, fetch)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = display.WriteJSON(w, v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().DurationVar(&serveTTL, "ttl", 30*time.Second, "How long to cache TfL responses")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"tfl/internal/cache"
+	"tfl/internal/tfl"
+)
+
+// fakeServeLookups replaces the /departures lookups for a test and counts
+// departure fetches.
+func fakeServeLookups(t *testing.T) *int {
+	resolve, departures := serveResolveStop, serveDepartures
+	t.Cleanup(func() { serveResolveStop, serveDepartures = resolve, departures })
+
+	fetches := 0
+	serveResolveStop = func(query string) (stopMatch, error) {
+		switch query {
+		case "nowhere":
+			return stopMatch{}, fmt.Errorf("%w matching '%s'", errNoStations, query)
+		case "offline":
+			return stopMatch{}, errors.New("searching stations: API returned status 503")
+		}
+		return stopMatch{Stop: tfl.StopPoint{ID: "940GZZLUPAC", Name: "Paddington Underground Station"}}, nil
+	}
+	serveDepartures = func(stopID string, q departureQuery) (departuresResult, error) {
+		fetches++
+		return departuresResult{Source: tfl.SourceRealtime, FetchedAt: time.Now()}, nil
+	}
+	return &fetches
+}
+
+func serveGet(t *testing.T, mux http.Handler, target string) (int, map[string]interface{}) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("GET %s: invalid JSON %q: %v", target, rec.Body.String(), err)
+	}
+	return rec.Code, body
+}
+
+func TestServeDeparturesParams(t *testing.T) {
+	fakeServeLookups(t)
+	mux := newServeMux(cache.New(time.Minute), cache.New(time.Minute))
+
+	tests := []struct {
+		name   string
+		params url.Values
+		want   int
+	}{
+		{"ok", url.Values{"station": {"paddington"}}, http.StatusOK},
+		{"missing station", url.Values{}, http.StatusBadRequest},
+		{"negative limit", url.Values{"station": {"paddington"}, "limit": {"-1"}}, http.StatusBadRequest},
+		{"bad blend", url.Values{"station": {"paddington"}, "blend": {"maybe"}}, http.StatusBadRequest},
+		{"bad time", url.Values{"station": {"paddington"}, "time": {"teatime"}}, http.StatusBadRequest},
+		{"time and until", url.Values{"station": {"paddington"}, "time": {"17:30"}, "until": {"18:15"}}, http.StatusOK},
+		{"bad until", url.Values{"station": {"paddington"}, "until": {"later"}}, http.StatusBadRequest},
+		{"bad window", url.Values{"station": {"paddington"}, "window": {"ninety"}}, http.StatusBadRequest},
+		{"until and window", url.Values{"station": {"paddington"}, "until": {"18:15"}, "window": {"90m"}}, http.StatusBadRequest},
+		{"filters", url.Values{"station": {"paddington"}, "line": {"central,bakerloo"}, "exclude": {"ealing"}}, http.StatusOK},
+		{"bad regex flag", url.Values{"station": {"paddington"}, "regex": {"yes please"}}, http.StatusBadRequest},
+		{"invalid regex", url.Values{"station": {"paddington"}, "line": {"(central"}, "regex": {"true"}}, http.StatusBadRequest},
+		{"station not found", url.Values{"station": {"nowhere"}}, http.StatusNotFound},
+		{"upstream failure", url.Values{"station": {"offline"}}, http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, body := serveGet(t, mux, "/departures?"+tt.params.Encode())
+			if code != tt.want {
+				t.Errorf("status = %d, want %d (body %v)", code, tt.want, body)
+			}
+			if code != http.StatusOK && body["error"] == nil {
+				t.Errorf("error response without an error message: %v", body)
+			}
+		})
+	}
+}
+
+func TestServeDeparturesCache(t *testing.T) {
+	fetches := fakeServeLookups(t)
+	mux := newServeMux(cache.New(time.Minute), cache.New(time.Minute))
+
+	for _, target := range []string{
+		"/departures?station=paddington&limit=3",
+		"/departures?station=paddington&limit=3",
+		"/departures?station=paddington&limit=5",
+		"/departures?station=paddington&limit=3&platform=1",
+		"/departures?station=paddington&limit=3&platform=1",
+	} {
+		if code, body := serveGet(t, mux, target); code != http.StatusOK {
+			t.Fatalf("GET %s = %d %v", target, code, body)
+		}
+	}
+	if *fetches != 3 {
+		t.Errorf("departures fetched %d times, want 3 (repeated queries served from cache)", *fetches)
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -16,16 +17,26 @@ var statusCmd = &cobra.Command{
 
 Examples:
   tfl status
-  tfl status --format json`,
+  tfl status --format json
+  tfl status --format csv
+  tfl status --format ndjson
+  tfl status --format markdown`,
 	Run: func(cmd *cobra.Command, args []string) {
 		statuses, err := client.GetTubeStatus()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		if IsJSON() {
+		switch {
+		case IsJSON():
 			display.PrintLineStatusesJSON(statuses)
-		} else {
+		case IsNDJSON():
+			display.PrintLineStatusesNDJSON(statuses, time.Now())
+		case IsTemplate():
+			display.PrintTemplate(outputTemplate, display.NewStatusOutput(statuses))
+		case IsTable():
+			printTable(display.LineStatusesTable(statuses))
+		default:
 			display.PrintLineStatuses(statuses)
 		}
 	},
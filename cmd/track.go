@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tfl/internal/display"
+	"tfl/internal/tfl"
+)
+
+var trackInterval time.Duration
+var trackOnce bool
+var trackLine string
+
+// trackVehicleArrivals fetches a vehicle's predictions; tests replace it.
+var trackVehicleArrivals = func(vehicleID string) ([]tfl.Arrival, error) {
+	return client.GetVehicleArrivals(vehicleID)
+}
+
+var trackCmd = &cobra.Command{
+	Use:   "track <vehicle-id>",
+	Short: "Follow a vehicle's remaining stops",
+	Long: `Follow a specific train or bus, showing its remaining stops with ETAs.
+
+Vehicle IDs are shown by "tfl departures --verbose". Predictions are refreshed
+until the vehicle terminates or is no longer tracked; a failed refresh is
+reported and retried at the next interval.
+
+Tube train numbers are only unique within a line, so the first prediction's
+line and destination pick the train; use --line to choose another line.
+--format json prints a single document, so it needs --once.
+
+Examples:
+  tfl track 202
+  tfl track 202 --line central --interval 15s
+  tfl track 202 --once --format json
+  tfl track 202 --once --format tsv`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{formatsAnnotation: "text,json,csv,tsv,markdown,html,template"},
+	Run: func(cmd *cobra.Command, args []string) {
+		vehicleID := args[0]
+		if err := checkTrackFlags(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		tracked := false
+		for {
+			arrivals, err := pollVehicle(vehicleID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				if trackOnce {
+					os.Exit(1)
+				}
+				time.Sleep(trackInterval)
+				continue
+			}
+
+			if len(arrivals) == 0 {
+				if !tracked {
+					fmt.Fprintf(os.Stderr, "No predictions found for vehicle '%s'\n", vehicleID)
+					os.Exit(1)
+				}
+				if outputFormat == "text" && !IsTemplate() {
+					fmt.Printf("Vehicle %s has terminated or is no longer tracked.\n", vehicleID)
+				}
+				return
+			}
+			tracked = true
+
+			switch {
+			case IsJSON():
+				display.PrintVehicleArrivalsJSON(arrivals, vehicleID)
+			case IsTemplate():
+				display.PrintTemplate(outputTemplate, display.NewVehicleOutput(arrivals, vehicleID))
+			case IsTable():
+				printTable(display.VehicleArrivalsTable(arrivals, vehicleID))
+			default:
+				if !trackOnce {
+					fmt.Print("\033[H\033[2J")
+				}
+				display.PrintVehicleArrivals(arrivals, vehicleID)
+			}
+
+			if trackOnce {
+				return
+			}
+			time.Sleep(trackInterval)
+		}
+	},
+}
+
+func checkTrackFlags() error {
+	if trackInterval < 5*time.Second {
+		return errors.New("--interval must be at least 5s")
+	}
+	if IsJSON() && !trackOnce {
+		return errors.New("--format json needs --once, as it prints a single document")
+	}
+	return nil
+}
+
+// pollVehicle returns the remaining stops of the train being tracked, on
+// --line if given.
+func pollVehicle(vehicleID string) ([]tfl.Arrival, error) {
+	arrivals, err := trackVehicleArrivals(vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	return tfl.VehicleTrip(arrivals, trackLine), nil
+}
+
+func init() {
+	trackCmd.Flags().DurationVarP(&trackInterval, "interval", "i", 30*time.Second, "Time between refreshes")
+	trackCmd.Flags().BoolVar(&trackOnce, "once", false, "Print the remaining stops once and exit")
+	trackCmd.Flags().StringVar(&trackLine, "line", "", "Line the train is on, when its number is used on several lines")
+	rootCmd.AddCommand(trackCmd)
+}
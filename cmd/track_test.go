@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"tfl/internal/tfl"
+)
+
+func TestCheckTrackFlags(t *testing.T) {
+	defer func(format string, interval time.Duration, once bool) {
+		outputFormat, trackInterval, trackOnce = format, interval, once
+	}(outputFormat, trackInterval, trackOnce)
+
+	tests := []struct {
+		format   string
+		interval time.Duration
+		once     bool
+		wantErr  bool
+	}{
+		{"text", 30 * time.Second, false, false},
+		{"text", time.Second, false, true},
+		{"json", 30 * time.Second, true, false},
+		{"json", 30 * time.Second, false, true},
+		{"tsv", 30 * time.Second, false, false},
+	}
+
+	for _, tt := range tests {
+		outputFormat, trackInterval, trackOnce = tt.format, tt.interval, tt.once
+		if err := checkTrackFlags(); (err != nil) != tt.wantErr {
+			t.Errorf("format %s, interval %v, once %v: err = %v, want error %v", tt.format, tt.interval, tt.once, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPollVehicle(t *testing.T) {
+	defer func(fetch func(string) ([]tfl.Arrival, error), line string) {
+		trackVehicleArrivals, trackLine = fetch, line
+	}(trackVehicleArrivals, trackLine)
+
+	trackVehicleArrivals = func(vehicleID string) ([]tfl.Arrival, error) {
+		if vehicleID != "202" {
+			return nil, errors.New("API returned status 503")
+		}
+		return []tfl.Arrival{
+			{LineID: "central", DestinationName: "Epping", StationName: "Bethnal Green"},
+			{LineID: "northern", DestinationName: "Morden", StationName: "Kennington"},
+			{LineID: "central", DestinationName: "Epping", StationName: "Mile End"},
+		}, nil
+	}
+
+	for _, tt := range []struct {
+		line string
+		want int
+	}{{"", 2}, {"northern", 1}, {"victoria", 0}} {
+		trackLine = tt.line
+		got, err := pollVehicle("202")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("--line %q: got %d stops, want %d", tt.line, len(got), tt.want)
+		}
+	}
+
+	if _, err := pollVehicle("999"); err == nil {
+		t.Error("expected the fetch error to be returned")
+	}
+}
@@ -0,0 +1,80 @@
+// Package cache holds short-lived API results in memory and coalesces
+// concurrent requests for the same key into a single fetch.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value   interface{}
+	err     error
+	expires time.Time
+	done    chan struct{}
+}
+
+type Cache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+func New(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, entries: make(map[string]*entry)}
+}
+
+// Get returns the cached value for key, calling fetch if there is none or
+// it has expired. Callers asking for a key that is already being fetched
+// wait for that fetch instead of starting another. Errors are returned to
+// every waiting caller but not cached.
+func (c *Cache) Get(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		select {
+		case <-e.done:
+			if time.Now().Before(e.expires) {
+				c.mu.Unlock()
+				return e.value, nil
+			}
+		default:
+			c.mu.Unlock()
+			<-e.done
+			return e.value, e.err
+		}
+	}
+
+	e := &entry{done: make(chan struct{})}
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	e.value, e.err = fetch()
+	e.expires = time.Now().Add(c.ttl)
+
+	c.mu.Lock()
+	if e.err != nil && c.entries[key] == e {
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+	close(e.done)
+
+	return e.value, e.err
+}
+
+// Prune removes expired entries so the cache does not grow without bound
+// as distinct queries come and go.
+func (c *Cache) Prune() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, e := range c.entries {
+		select {
+		case <-e.done:
+			if now.After(e.expires) {
+				delete(c.entries, key)
+			}
+		default:
+		}
+	}
+}
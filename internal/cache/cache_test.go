@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetCachesWithinTTL(t *testing.T) {
+	c := New(time.Minute)
+	var calls int32
+	fetch := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return "value", nil
+	}
+
+	for i := 0; i < 3; i++ {
+		v, err := c.Get("key", fetch)
+		if err != nil || v != "value" {
+			t.Fatalf("Get() = %v, %v", v, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+}
+
+func TestGetRefetchesAfterTTL(t *testing.T) {
+	c := New(time.Millisecond)
+	var calls int32
+	fetch := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, nil
+	}
+
+	_, _ = c.Get("key", fetch)
+	time.Sleep(5 * time.Millisecond)
+	_, _ = c.Get("key", fetch)
+
+	if calls != 2 {
+		t.Errorf("fetch called %d times, want 2", calls)
+	}
+}
+
+func TestGetCoalescesConcurrentRequests(t *testing.T) {
+	c := New(time.Minute)
+	var calls int32
+	release := make(chan struct{})
+	fetch := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, _ := c.Get("key", fetch); v != 42 {
+				t.Errorf("Get() = %v, want 42", v)
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+}
+
+func TestGetDoesNotCacheErrors(t *testing.T) {
+	c := New(time.Minute)
+	var calls int32
+	fetch := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, errors.New("boom")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get("key", fetch); err == nil {
+			t.Fatal("Get() error = nil, want error")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("fetch called %d times, want 2", calls)
+	}
+}
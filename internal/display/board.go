@@ -0,0 +1,137 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+
+	"tfl/internal/tfl"
+)
+
+// boardDepth is the number of trains shown on each platform board.
+const boardDepth = 3
+
+// Board is one platform's departures for the board style, with the
+// calling points of the first train if known.
+type Board struct {
+	Platform      string
+	Arrivals      []tfl.Arrival
+	CallingPoints []string
+}
+
+// NewBoards groups arrivals into a board per platform, each with the next
+// boardDepth trains.
+func NewBoards(arrivals []tfl.Arrival) []Board {
+	groups, _ := GroupArrivals(arrivals, "platform", boardDepth)
+	boards := make([]Board, 0, len(groups))
+	for _, g := range groups {
+		boards = append(boards, Board{Platform: g.Title, Arrivals: g.Arrivals})
+	}
+	return boards
+}
+
+// CallingPoints returns the stations a train calls at after arr, from the
+// vehicle's predictions as returned by GetVehicleArrivals. Train numbers are
+// only unique within a line, so predictions for other lines, or for another
+// destination where both are known, belong to a different train.
+func CallingPoints(arr tfl.Arrival, vehicleArrivals []tfl.Arrival) []string {
+	var stops []string
+	for _, v := range vehicleArrivals {
+		if v.LineID != arr.LineID {
+			continue
+		}
+		if v.DestinationName != "" && arr.DestinationName != "" && v.DestinationName != arr.DestinationName {
+			continue
+		}
+		if v.ExpectedArrival.After(arr.ExpectedArrival) {
+			stops = append(stops, shortStationName(v.StationName))
+		}
+	}
+	return stops
+}
+
+var (
+	boardAmber = rgb{255, 176, 0}
+	boardBlack = rgb{0, 0, 0}
+)
+
+// PrintBoards renders departures as amber dot-matrix style platform boards:
+// the next train and its minutes away, the two after it beneath, and a
+// calling-points line. Calling points longer than the board are split into
+// pages; page selects which one is shown, wrapping around.
+func PrintBoards(boards []Board, stationName string, page int) {
+	fmt.Println()
+	if len(boards) == 0 {
+		fmt.Printf("%sNo arrivals found for %s%s\n\n", yellow, stationName, reset)
+		return
+	}
+
+	width := terminalWidth() - 2
+	if width > 56 {
+		width = 56
+	}
+	if width < 30 {
+		width = 30
+	}
+
+	style, end := "", ""
+	if colorProfile != ColorNone {
+		style, end = background(boardBlack)+foreground(boardAmber)+bold, reset
+	}
+	line := func(text string) {
+		fmt.Printf(" %s %s %s\n", style, fitCell(text, width-2), end)
+	}
+
+	fmt.Printf("%s%s %s %s\n\n", bold, white, stationName, reset)
+	for _, b := range boards {
+		line(strings.ToUpper(b.Platform))
+		for i, arr := range b.Arrivals {
+			line(boardRow(i+1, arr, width-2))
+		}
+		if len(b.CallingPoints) > 0 {
+			pages := callingPointPages(b.CallingPoints, width-2)
+			line(pages[page%len(pages)])
+		}
+		fmt.Println()
+	}
+}
+
+// boardRow formats "1  Ealing Broadway     3 min" to width columns.
+func boardRow(n int, arr tfl.Arrival, width int) string {
+	due := boardDue(arr.TimeToStation / 60)
+	prefix := fmt.Sprintf("%d  ", n)
+	dest := width - displayWidth(prefix) - displayWidth(due) - 1
+	if dest < 1 {
+		dest = 1
+	}
+	return prefix + fitCell(arr.DestinationName, dest) + " " + due
+}
+
+// boardDue is the minutes-away text used on station boards.
+func boardDue(mins int) string {
+	if mins <= 0 {
+		return "Due"
+	}
+	return fmt.Sprintf("%d min", mins)
+}
+
+// callingPointPages splits "Calling at: A, B, C" into pages of at most
+// width columns, breaking only between stations.
+func callingPointPages(points []string, width int) []string {
+	var pages []string
+	current, stations := "Calling at:", 0
+	for i, p := range points {
+		item := p
+		if i < len(points)-1 {
+			item += ","
+		}
+		switch {
+		case stations > 0 && displayWidth(current)+1+displayWidth(item) > width:
+			pages = append(pages, current)
+			current, stations = item, 1
+		default:
+			current += " " + item
+			stations++
+		}
+	}
+	return append(pages, current)
+}
@@ -0,0 +1,79 @@
+package display
+
+import (
+	"testing"
+	"time"
+
+	"tfl/internal/tfl"
+)
+
+func TestBoardRow(t *testing.T) {
+	tests := []struct {
+		n    int
+		arr  tfl.Arrival
+		want string
+	}{
+		{1, tfl.Arrival{DestinationName: "Ealing Broadway", TimeToStation: 180}, "1  Ealing Broadway     3 min"},
+		{2, tfl.Arrival{DestinationName: "West Ruislip", TimeToStation: 20}, "2  West Ruislip          Due"},
+		{3, tfl.Arrival{DestinationName: "Heathrow Terminal 5", TimeToStation: 720}, "3  Heathrow Termina.. 12 min"},
+	}
+
+	for _, tt := range tests {
+		got := boardRow(tt.n, tt.arr, 28)
+		if got != tt.want {
+			t.Errorf("boardRow() = %q, want %q", got, tt.want)
+		}
+		if displayWidth(got) != 28 {
+			t.Errorf("boardRow() is %d columns, want 28", displayWidth(got))
+		}
+	}
+}
+
+func TestNewBoards(t *testing.T) {
+	var arrivals []tfl.Arrival
+	for i := 0; i < 5; i++ {
+		arrivals = append(arrivals, tfl.Arrival{PlatformName: "Platform 1", TimeToStation: i * 60})
+	}
+	arrivals = append(arrivals, tfl.Arrival{PlatformName: "Platform 2"})
+
+	boards := NewBoards(arrivals)
+	if len(boards) != 2 {
+		t.Fatalf("got %d boards, want 2", len(boards))
+	}
+	if boards[0].Platform != "Platform 1" || len(boards[0].Arrivals) != boardDepth {
+		t.Errorf("board 0 = %q with %d trains", boards[0].Platform, len(boards[0].Arrivals))
+	}
+}
+
+func TestCallingPoints(t *testing.T) {
+	base := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	here := tfl.Arrival{LineID: "hammersmith-city", DestinationName: "Hammersmith", StationName: "Paddington Underground Station", ExpectedArrival: base.Add(2 * time.Minute)}
+	vehicle := []tfl.Arrival{
+		{LineID: "hammersmith-city", DestinationName: "Hammersmith", StationName: "Edgware Road (Circle Line) Underground Station", ExpectedArrival: base},
+		here,
+		{LineID: "hammersmith-city", DestinationName: "Hammersmith", StationName: "Royal Oak Underground Station", ExpectedArrival: base.Add(4 * time.Minute)},
+		{LineID: "central", DestinationName: "Epping", StationName: "Leytonstone Underground Station", ExpectedArrival: base.Add(5 * time.Minute)},
+		{LineID: "hammersmith-city", DestinationName: "Barking", StationName: "Aldgate East Underground Station", ExpectedArrival: base.Add(5 * time.Minute)},
+		{LineID: "hammersmith-city", StationName: "Westbourne Park Underground Station", ExpectedArrival: base.Add(6 * time.Minute)},
+	}
+
+	got := CallingPoints(here, vehicle)
+	if len(got) != 2 || got[0] != "Royal Oak" || got[1] != "Westbourne Park" {
+		t.Errorf("CallingPoints() = %q", got)
+	}
+}
+
+func TestCallingPointPages(t *testing.T) {
+	points := []string{"Royal Oak", "Westbourne Park", "Ladbroke Grove", "Latimer Road"}
+	want := []string{"Calling at: Royal Oak,", "Westbourne Park, Ladbroke Grove,", "Latimer Road"}
+
+	pages := callingPointPages(points, 32)
+	if len(pages) != len(want) {
+		t.Fatalf("callingPointPages() = %q", pages)
+	}
+	for i := range pages {
+		if pages[i] != want[i] {
+			t.Errorf("page %d = %q, want %q", i, pages[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ColorProfile is the colour depth used for text output.
+type ColorProfile int
+
+const (
+	ColorNone ColorProfile = iota
+	Color16
+	Color256
+	ColorTrue
+)
+
+var colorProfile = ColorTrue
+
+// Color modes accepted by --color.
+var ColorModes = []string{"auto", "always", "never"}
+
+// DetectColorProfile picks the colour depth for mode (auto, always or
+// never). In auto mode colour is off when stdout is not a terminal, when
+// NO_COLOR is set or when TERM is dumb. The depth comes from COLORTERM and
+// TERM.
+func DetectColorProfile(mode string, tty bool, getenv func(string) string) (ColorProfile, error) {
+	switch mode {
+	case "never":
+		return ColorNone, nil
+	case "always":
+		if p := envColorDepth(getenv); p != ColorNone {
+			return p, nil
+		}
+		return Color16, nil
+	case "auto", "":
+		if !tty || getenv("NO_COLOR") != "" {
+			return ColorNone, nil
+		}
+		return envColorDepth(getenv), nil
+	default:
+		return ColorNone, fmt.Errorf("unknown color mode '%s' (use %s)", mode, strings.Join(ColorModes, ", "))
+	}
+}
+
+func envColorDepth(getenv func(string) string) ColorProfile {
+	term := getenv("TERM")
+	switch colorterm := strings.ToLower(getenv("COLORTERM")); {
+	case term == "dumb":
+		return ColorNone
+	case colorterm == "truecolor" || colorterm == "24bit":
+		return ColorTrue
+	case strings.Contains(term, "256color"):
+		return Color256
+	default:
+		return Color16
+	}
+}
+
+// IsTerminal reports whether f is a character device such as a terminal.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// SetColorProfile sets the colour depth of text output. ColorNone removes
+// all escape sequences.
+func SetColorProfile(p ColorProfile) {
+	colorProfile = p
+	if p == ColorNone {
+		reset, bold, red, green, yellow, blue, magenta, cyan, white, gray, dim = "", "", "", "", "", "", "", "", "", "", ""
+		statusColors = statusPalette{}
+		return
+	}
+	reset, bold, dim = "\033[0m", "\033[1m", "\033[2m"
+	red, green, yellow, blue = "\033[31m", "\033[32m", "\033[33m", "\033[34m"
+	magenta, cyan, white, gray = "\033[35m", "\033[36m", "\033[37m", "\033[90m"
+	statusColors = themeStatus.palette()
+}
+
+// background returns the escape sequence for a background colour at the
+// current colour depth.
+func background(c rgb) string {
+	switch colorProfile {
+	case ColorTrue:
+		return fmt.Sprintf("\033[48;2;%d;%d;%dm", c.r, c.g, c.b)
+	case Color256:
+		return fmt.Sprintf("\033[48;5;%dm", ansi256(c))
+	case Color16:
+		i := ansi16(c)
+		if i < 8 {
+			return fmt.Sprintf("\033[%dm", 40+i)
+		}
+		return fmt.Sprintf("\033[%dm", 100+i-8)
+	}
+	return ""
+}
+
+// foreground returns the escape sequence for a text colour at the current
+// colour depth.
+func foreground(c rgb) string {
+	switch colorProfile {
+	case ColorTrue:
+		return fmt.Sprintf("\033[38;2;%d;%d;%dm", c.r, c.g, c.b)
+	case Color256:
+		return fmt.Sprintf("\033[38;5;%dm", ansi256(c))
+	case Color16:
+		i := ansi16(c)
+		if i < 8 {
+			return fmt.Sprintf("\033[%dm", 30+i)
+		}
+		return fmt.Sprintf("\033[%dm", 90+i-8)
+	}
+	return ""
+}
+
+// cubeLevels are the channel values of the 6x6x6 cube in the xterm
+// 256-colour palette.
+var cubeLevels = []int{0, 95, 135, 175, 215, 255}
+
+// ansi256 maps c to the nearest colour in the 256-colour palette's cube.
+func ansi256(c rgb) int {
+	level := func(v uint8) int {
+		best := 0
+		for i, l := range cubeLevels {
+			if abs(int(v)-l) < abs(int(v)-cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	return 16 + 36*level(c.r) + 6*level(c.g) + level(c.b)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// ansi16Colors are the usual xterm values of the 16 basic ANSI colours.
+var ansi16Colors = []rgb{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// ansi16 returns the index of the basic ANSI colour nearest to c.
+func ansi16(c rgb) int {
+	best, bestDist := 0, -1
+	for i, a := range ansi16Colors {
+		dr, dg, db := int(c.r)-int(a.r), int(c.g)-int(a.g), int(c.b)-int(a.b)
+		if d := dr*dr + dg*dg + db*db; bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// statusPalette holds the escape sequences for good service, minor delays
+// and severe disruption.
+type statusPalette struct {
+	good, minor, severe string
+}
+
+var statusColors = statusPalette{green, yellow, red}
+
+// themeStatus is the status colours set by a theme; unset entries keep the
+// terminal's own green, yellow and red.
+var themeStatus ThemeStatus
+
+// Theme overrides line and status colours. Colours are "#rrggbb".
+type Theme struct {
+	Lines  map[string]ThemeLine `json:"lines"`
+	Status ThemeStatus          `json:"status"`
+}
+
+type ThemeLine struct {
+	Background string `json:"background"`
+	DarkText   bool   `json:"dark_text"`
+}
+
+type ThemeStatus struct {
+	Good   string `json:"good"`
+	Minor  string `json:"minor"`
+	Severe string `json:"severe"`
+}
+
+func (s ThemeStatus) palette() statusPalette {
+	p := statusPalette{"\033[32m", "\033[33m", "\033[31m"}
+	if c, err := parseHex(s.Good); err == nil {
+		p.good = foreground(c)
+	}
+	if c, err := parseHex(s.Minor); err == nil {
+		p.minor = foreground(c)
+	}
+	if c, err := parseHex(s.Severe); err == nil {
+		p.severe = foreground(c)
+	}
+	return p
+}
+
+// Themes are the built-in themes. "colorblind" uses the Okabe-Ito palette
+// for status, which stays distinguishable with red-green colour blindness.
+var Themes = map[string]Theme{
+	"default": {},
+	"colorblind": {
+		Status: ThemeStatus{Good: "#0072b2", Minor: "#e69f00", Severe: "#d55e00"},
+	},
+}
+
+// LoadTheme returns the built-in theme called name, or reads a theme from
+// the JSON file at name.
+func LoadTheme(name string) (Theme, error) {
+	if t, ok := Themes[name]; ok {
+		return t, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return Theme{}, fmt.Errorf("loading theme: %w", err)
+	}
+	var t Theme
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Theme{}, fmt.Errorf("parsing theme %s: %w", name, err)
+	}
+	return t, nil
+}
+
+// ApplyTheme overrides line and status colours with those set in t.
+func ApplyTheme(t Theme) error {
+	// Validate everything before changing anything, so a bad theme leaves
+	// the built-in colours in place.
+	colors := make(map[string]lineColor, len(t.Lines))
+	for id, line := range t.Lines {
+		c, err := parseHex(line.Background)
+		if err != nil {
+			return fmt.Errorf("theme line %s: %w", id, err)
+		}
+		colors[id] = lineColor{c, line.DarkText}
+	}
+	for _, hex := range []string{t.Status.Good, t.Status.Minor, t.Status.Severe} {
+		if hex == "" {
+			continue
+		}
+		if _, err := parseHex(hex); err != nil {
+			return fmt.Errorf("theme status: %w", err)
+		}
+	}
+
+	for id, c := range colors {
+		lineColors[id] = c
+	}
+	themeStatus = t.Status
+	if colorProfile != ColorNone {
+		statusColors = themeStatus.palette()
+	}
+	return nil
+}
+
+func parseHex(s string) (rgb, error) {
+	var c rgb
+	if len(s) != 7 || s[0] != '#' {
+		return c, fmt.Errorf("invalid colour %q (use #rrggbb)", s)
+	}
+	if _, err := fmt.Sscanf(s[1:], "%02x%02x%02x", &c.r, &c.g, &c.b); err != nil {
+		return c, fmt.Errorf("invalid colour %q (use #rrggbb)", s)
+	}
+	return c, nil
+}
@@ -0,0 +1,114 @@
+package display
+
+import "testing"
+
+func TestDetectColorProfile(t *testing.T) {
+	tests := []struct {
+		name string
+		mode string
+		tty  bool
+		env  map[string]string
+		want ColorProfile
+	}{
+		{"never", "never", true, map[string]string{"COLORTERM": "truecolor"}, ColorNone},
+		{"auto not a tty", "auto", false, map[string]string{"COLORTERM": "truecolor"}, ColorNone},
+		{"auto NO_COLOR", "auto", true, map[string]string{"NO_COLOR": "1", "COLORTERM": "truecolor"}, ColorNone},
+		{"auto dumb terminal", "auto", true, map[string]string{"TERM": "dumb"}, ColorNone},
+		{"auto truecolor", "auto", true, map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, ColorTrue},
+		{"auto 256", "auto", true, map[string]string{"TERM": "xterm-256color"}, Color256},
+		{"auto 16", "auto", true, map[string]string{"TERM": "xterm"}, Color16},
+		{"always not a tty", "always", false, map[string]string{"COLORTERM": "24bit"}, ColorTrue},
+		{"always dumb terminal", "always", false, map[string]string{"TERM": "dumb"}, Color16},
+		{"always ignores NO_COLOR", "always", true, map[string]string{"NO_COLOR": "1", "TERM": "screen-256color"}, Color256},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectColorProfile(tt.mode, tt.tty, func(k string) string { return tt.env[k] })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("DetectColorProfile() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := DetectColorProfile("sometimes", true, func(string) string { return "" }); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
+
+func TestPaletteFallback(t *testing.T) {
+	tests := []struct {
+		c       rgb
+		want16  int
+		want256 int
+	}{
+		{rgb{0, 0, 0}, 0, 16},
+		{rgb{255, 255, 255}, 15, 231},
+		{rgb{220, 36, 31}, 1, 160},
+		{rgb{0, 160, 226}, 6, 38},
+	}
+
+	for _, tt := range tests {
+		if got := ansi16(tt.c); got != tt.want16 {
+			t.Errorf("ansi16(%v) = %d, want %d", tt.c, got, tt.want16)
+		}
+		if got := ansi256(tt.c); got != tt.want256 {
+			t.Errorf("ansi256(%v) = %d, want %d", tt.c, got, tt.want256)
+		}
+	}
+}
+
+func TestSetColorProfileNone(t *testing.T) {
+	defer SetColorProfile(ColorTrue)
+
+	SetColorProfile(ColorNone)
+	if reset != "" || bold != "" || getLineColor("central") != "" || statusColor(1) != "" {
+		t.Error("escape sequences left with colour off")
+	}
+
+	SetColorProfile(Color256)
+	if got, want := getLineColor("central"), "\033[48;5;160m\033[97m"; got != want {
+		t.Errorf("getLineColor() = %q, want %q", got, want)
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	saved := lineColors["central"]
+	defer func() {
+		lineColors["central"] = saved
+		ApplyTheme(Theme{})
+	}()
+
+	err := ApplyTheme(Theme{
+		Lines:  map[string]ThemeLine{"central": {Background: "#102030", DarkText: true}},
+		Status: Themes["colorblind"].Status,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := lookupLineColor("central"); got != (lineColor{rgb{16, 32, 48}, true}) {
+		t.Errorf("central = %+v", got)
+	}
+	if got, want := statusColor(10), "\033[38;2;0;114;178m"; got != want {
+		t.Errorf("good status = %q, want %q", got, want)
+	}
+
+	if err := ApplyTheme(Theme{Status: ThemeStatus{Good: "blue"}}); err == nil {
+		t.Error("expected error for invalid colour")
+	}
+
+	// An invalid theme changes nothing, even colours that were valid.
+	err = ApplyTheme(Theme{
+		Lines:  map[string]ThemeLine{"central": {Background: "#ffffff"}},
+		Status: ThemeStatus{Good: "blue"},
+	})
+	if err == nil {
+		t.Error("expected error for invalid colour")
+	}
+	if got := lookupLineColor("central"); got != (lineColor{rgb{16, 32, 48}, true}) {
+		t.Errorf("central after invalid theme = %+v", got)
+	}
+}
@@ -2,12 +2,15 @@ package display
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"tfl/internal/tfl"
 )
 
-const (
+// Escape sequences used by the text output. SetColorProfile blanks them
+// when colour is off.
+var (
 	reset   = "\033[0m"
 	bold    = "\033[1m"
 	red     = "\033[31m"
@@ -18,40 +21,68 @@ const (
 	cyan    = "\033[36m"
 	white   = "\033[37m"
 	gray    = "\033[90m"
+	dim     = "\033[2m"
 )
 
-var lineColors = map[string]string{
-	"bakerloo":          "\033[48;2;178;99;0m\033[97m",
-	"central":           "\033[48;2;220;36;31m\033[97m",
-	"circle":            "\033[48;2;255;211;0m\033[30m",
-	"district":          "\033[48;2;0;125;50m\033[97m",
-	"hammersmith-city":  "\033[48;2;244;169;190m\033[30m",
-	"jubilee":           "\033[48;2;161;165;167m\033[30m",
-	"metropolitan":      "\033[48;2;155;0;88m\033[97m",
-	"northern":          "\033[48;2;0;0;0m\033[97m",
-	"piccadilly":        "\033[48;2;0;54;136m\033[97m",
-	"victoria":          "\033[48;2;0;160;226m\033[97m",
-	"waterloo-city":     "\033[48;2;147;206;186m\033[30m",
-	"elizabeth":         "\033[48;2;107;63;160m\033[97m",
-	"dlr":               "\033[48;2;0;175;173m\033[97m",
-	"london-overground": "\033[48;2;239;123;16m\033[30m",
+type rgb struct {
+	r, g, b uint8
 }
 
-func getLineColor(lineID string) string {
+func (c rgb) hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b)
+}
+
+// lineColor is a line's brand colour, with dark text for light backgrounds.
+type lineColor struct {
+	bg       rgb
+	darkText bool
+}
+
+// lineColors are the line colours in use: the registry's, overridden by
+// any theme.
+var lineColors = registryColors()
+
+var defaultLineColor = lineColor{rgb{100, 100, 100}, false}
+
+func lookupLineColor(lineID string) lineColor {
+	return lookupModeLineColor(lineID, "")
+}
+
+// lookupModeLineColor is lookupLineColor with a fallback to the colour of
+// the line's mode, e.g. for bus routes.
+func lookupModeLineColor(lineID, mode string) lineColor {
 	if color, ok := lineColors[lineID]; ok {
 		return color
 	}
-	return "\033[48;2;100;100;100m\033[97m"
+	if color, ok := modeColors[mode]; ok {
+		return color
+	}
+	return defaultLineColor
+}
+
+func getLineColor(lineID string) string {
+	return lineColorEscape(lookupLineColor(lineID))
+}
+
+func lineColorEscape(c lineColor) string {
+	if colorProfile == ColorNone {
+		return ""
+	}
+	fg := "\033[97m"
+	if c.darkText {
+		fg = "\033[30m"
+	}
+	return background(c.bg) + fg
 }
 
 func statusColor(severity int) string {
 	switch {
 	case severity == 10:
-		return green
+		return statusColors.good
 	case severity >= 6 && severity <= 9:
-		return yellow
+		return statusColors.minor
 	default:
-		return red
+		return statusColors.severe
 	}
 }
 
@@ -60,17 +91,17 @@ func PrintLineStatuses(statuses []tfl.LineStatus) {
 	fmt.Printf("%s%s TfL Tube Status %s\n\n", bold, white, reset)
 
 	for _, line := range statuses {
-		lineCol := getLineColor(line.ID)
+		lineCol := lineColorEscape(lookupModeLineColor(line.ID, line.ModeName))
 		status := line.LineStatuses[0]
 		statCol := statusColor(status.StatusSeverity)
 
-		lineName := formatLineName(line.Name)
-		fmt.Printf("%s%s%s %s%-20s%s\n",
+		lineName := formatLineName(displayLineName(line.ID, line.Name))
+		fmt.Printf("%s%s%s %s%s%s\n",
 			lineCol, lineName, reset,
 			statCol, status.StatusSeverityDescription, reset)
 
 		if status.Reason != "" {
-			reason := wrapText(status.Reason, 60)
+			reason := wrapText(status.Reason, fitWidth(60, 2))
 			for _, l := range reason {
 				fmt.Printf("  %s%s%s\n", gray, l, reset)
 			}
@@ -104,7 +135,7 @@ func PrintDisruptions(disruptions []tfl.Disruption) {
 		}
 
 		fmt.Printf("%s[%s]%s %s%s%s\n", color, icon, reset, bold, d.CategoryDescription, reset)
-		lines := wrapText(d.Description, 70)
+		lines := wrapText(d.Description, fitWidth(70, 4))
 		for _, l := range lines {
 			fmt.Printf("    %s\n", l)
 		}
@@ -121,32 +152,81 @@ func PrintStopPoints(stops []tfl.StopPoint) {
 	fmt.Println()
 	fmt.Printf("%s%s Stations found: %s\n\n", bold, white, reset)
 
+	nameWidth := fitWidth(40, 30)
 	for _, stop := range stops {
 		modes := strings.Join(stop.Modes, ", ")
 		zone := stop.Zone
 		if zone == "" {
 			zone = "-"
 		}
-		fmt.Printf("  %s%-40s%s Zone: %s  [%s]\n", cyan, stop.Name, reset, zone, modes)
+		if compact() {
+			fmt.Printf("  %s%s%s\n  Zone: %s  [%s]\n", cyan, truncateWidth(stop.Name, fitWidth(pipeWidth, 2)), reset, zone, modes)
+		} else {
+			fmt.Printf("  %s%s%s Zone: %s  [%s]\n", cyan, fitColumn(stop.Name, nameWidth), reset, zone, modes)
+		}
 		fmt.Printf("  %sID: %s%s\n\n", gray, stop.ID, reset)
 	}
 }
 
-const lineNameWidth = 14
+const (
+	lineNameWidth        = 14
+	compactLineNameWidth = 8
+)
 
+// formatLineName pads or truncates a line name to the badge width, which is
+// narrower in the compact layout.
 func formatLineName(name string) string {
-	if len(name) > lineNameWidth {
-		return " " + name[:lineNameWidth-2] + ".. "
+	width := lineNameWidth
+	if compact() {
+		width = compactLineNameWidth
+	}
+	return " " + fitCell(name, width) + " "
+}
+
+// arrivalLayout holds the column widths of the departures list for the
+// current terminal width.
+type arrivalLayout struct {
+	compact   bool
+	destWidth int
+	// platformWidth is 0 when there is no room for the platform.
+	platformWidth int
+	// piped pads the destination without truncating it.
+	piped bool
+}
+
+// arrivalFixedWidth is the width of the line badge, clock and
+// minutes-away columns with their separators.
+const arrivalFixedWidth = lineNameWidth + 2 + 2 + 5 + 2 + 8 + 2
+
+func newArrivalLayout(width int) arrivalLayout {
+	if width < compactWidth {
+		// Badge, minutes away and the destination in what's left.
+		dest := width - (compactLineNameWidth + 2) - 1 - 7 - 1
+		if dest < 8 {
+			dest = 8
+		}
+		return arrivalLayout{compact: true, destWidth: dest}
 	}
-	// Pad to fixed width
-	padded := " " + name
-	for len(padded) < lineNameWidth+1 {
-		padded += " "
+
+	if width >= pipeWidth {
+		return arrivalLayout{destWidth: 28, platformWidth: pipeWidth, piped: true}
 	}
-	return padded + " "
+
+	dest := width - arrivalFixedWidth - 14
+	switch {
+	case dest > 28:
+		dest = 28
+	case dest < 12:
+		dest = 12
+	}
+	platform := width - arrivalFixedWidth - dest - 2
+	if platform < 0 {
+		platform = 0
+	}
+	return arrivalLayout{destWidth: dest, platformWidth: platform}
 }
 
-func PrintArrivals(arrivals []tfl.Arrival, stationName string) {
+func PrintArrivals(arrivals []tfl.Arrival, stationName string, verbose bool) {
 	fmt.Println()
 	if len(arrivals) == 0 {
 		fmt.Printf("%sNo arrivals found for %s%s\n\n", yellow, stationName, reset)
@@ -155,41 +235,122 @@ func PrintArrivals(arrivals []tfl.Arrival, stationName string) {
 
 	fmt.Printf("%s%s Departures from %s %s\n\n", bold, white, stationName, reset)
 
+	layout := newArrivalLayout(terminalWidth())
 	for _, arr := range arrivals {
-		lineCol := getLineColor(arr.LineID)
-		mins := arr.TimeToStation / 60
-		departureTime := arr.ExpectedArrival.Local().Format("15:04")
-
-		var timeStr string
-		switch {
-		case mins == 0:
-			timeStr = fmt.Sprintf("%sDue%s", green+bold, reset)
-		case mins == 1:
-			timeStr = fmt.Sprintf("%s1 min%s", green, reset)
-		case mins < 60:
-			timeStr = fmt.Sprintf("%d mins", mins)
-		default:
-			hours := mins / 60
-			remainMins := mins % 60
-			if remainMins == 0 {
-				timeStr = fmt.Sprintf("%dh", hours)
-			} else {
-				timeStr = fmt.Sprintf("%dh %dm", hours, remainMins)
-			}
-		}
+		printArrival(arr, layout, verbose)
+	}
+	fmt.Println()
+}
 
-		platform := arr.PlatformName
-		if platform == "" {
-			platform = "-"
+// PrintArrivalGroups prints departures in a section per group, as made by
+// GroupArrivals.
+func PrintArrivalGroups(groups []ArrivalGroup, stationName string, verbose bool) {
+	fmt.Println()
+	if len(groups) == 0 {
+		fmt.Printf("%sNo arrivals found for %s%s\n\n", yellow, stationName, reset)
+		return
+	}
+
+	fmt.Printf("%s%s Departures from %s %s\n", bold, white, stationName, reset)
+
+	layout := newArrivalLayout(terminalWidth())
+	for _, g := range groups {
+		fmt.Printf("\n%s%s%s\n", bold, g.Title, reset)
+		for _, arr := range g.Arrivals {
+			printArrival(arr, layout, verbose)
 		}
+	}
+	fmt.Println()
+}
+
+func printArrival(arr tfl.Arrival, layout arrivalLayout, verbose bool) {
+	lineCol := getLineColor(arr.LineID)
+	departureTime := arr.ExpectedArrival.Local().Format("15:04")
+	timeStr := colorMinutes(arr.TimeToStation / 60)
+
+	platform := arr.PlatformName
+	if platform == "" {
+		platform = "-"
+	}
 
-		lineName := formatLineName(arr.LineName)
-		fmt.Printf("%s%s%s  %s%s%s  %-8s  %s%-28s%s  %s%s%s\n",
+	lineName := formatLineName(displayLineName(arr.LineID, arr.LineName))
+	dest := fitCell(arr.DestinationName, layout.destWidth)
+	if layout.piped {
+		dest = padWidth(arr.DestinationName, layout.destWidth)
+	}
+	switch {
+	case layout.compact && arr.Scheduled():
+		fmt.Printf("%s%s%s %s%s %s%s\n",
+			lineCol, lineName, reset,
+			dim, padWidth(relTime(arr.TimeToStation/60), 7), truncateWidth(arr.DestinationName, layout.destWidth), reset)
+		return
+	case layout.compact:
+		fmt.Printf("%s%s%s %s %s%s%s\n",
+			lineCol, lineName, reset,
+			padWidth(timeStr, 7),
+			bold, truncateWidth(arr.DestinationName, layout.destWidth), reset)
+	case arr.Scheduled():
+		// Timetable entries have no platform or live tracking, so show
+		// them dimmed with a marker in place of the platform.
+		fmt.Printf("%s%s%s  %s%s  %s  %s  %s%s\n",
+			lineCol, lineName, reset,
+			dim, departureTime,
+			padWidth(relTime(arr.TimeToStation/60), 8),
+			dest, truncateWidth("sched", layout.platformWidth), reset)
+		return
+	default:
+		fmt.Printf("%s%s%s  %s%s%s  %s  %s%s%s  %s%s%s\n",
 			lineCol, lineName, reset,
 			cyan, departureTime, reset,
-			timeStr,
-			bold, arr.DestinationName, reset,
-			gray, platform, reset)
+			padWidth(timeStr, 8),
+			bold, dest, reset,
+			gray, truncateWidth(platform, layout.platformWidth), reset)
+	}
+
+	if verbose && (arr.VehicleID != "" || arr.CurrentLocation != "") {
+		vehicle := arr.VehicleID
+		if vehicle == "" {
+			vehicle = "-"
+		}
+		fmt.Printf("%s%*s  vehicle %s  %s%s\n", gray, lineNameWidth+1, "", vehicle, arr.CurrentLocation, reset)
+	}
+}
+
+// colorMinutes is relTime with the board's highlight for trains that are
+// due or a minute away.
+func colorMinutes(mins int) string {
+	text := relTime(mins)
+	switch {
+	case mins <= 0:
+		return green + bold + text + reset
+	case mins == 1:
+		return green + text + reset
+	}
+	return text
+}
+
+func PrintVehicleArrivals(arrivals []tfl.Arrival, vehicleID string) {
+	fmt.Println()
+	if len(arrivals) == 0 {
+		fmt.Printf("%sNo predictions for vehicle %s%s\n\n", yellow, vehicleID, reset)
+		return
+	}
+
+	first := arrivals[0]
+	lineCol := getLineColor(first.LineID)
+	fmt.Printf("%s%s%s %s%s Vehicle %s to %s %s\n", lineCol, formatLineName(first.LineName), reset, bold, white, vehicleID, first.DestinationName, reset)
+	if first.CurrentLocation != "" {
+		fmt.Printf("  %s%s%s\n", gray, first.CurrentLocation, reset)
+	}
+	fmt.Println()
+
+	stationWidth := fitWidth(pipeWidth, 19)
+	for _, arr := range arrivals {
+		arrivalTime := arr.ExpectedArrival.Local().Format("15:04")
+		fmt.Printf("  %s%s%s  %s  %s\n",
+			cyan, arrivalTime, reset,
+			padWidth(colorMinutes(arr.TimeToStation/60), 8),
+			truncateWidth(shortStationName(arr.StationName), stationWidth))
 	}
 	fmt.Println()
 }
@@ -203,7 +364,7 @@ func wrapText(text string, width int) []string {
 
 	currentLine := words[0]
 	for _, word := range words[1:] {
-		if len(currentLine)+1+len(word) <= width {
+		if displayWidth(currentLine)+1+displayWidth(word) <= width {
 			currentLine += " " + word
 		} else {
 			lines = append(lines, currentLine)
@@ -213,3 +374,130 @@ func wrapText(text string, width int) []string {
 	lines = append(lines, currentLine)
 	return lines
 }
+
+// walkingSpeed is an average walking pace in metres per minute, used to
+// turn straight-line distances into a rough walking time.
+const walkingSpeed = 80
+
+func PrintNearbyStops(stops []tfl.NearbyStopPoint, lat, lon float64, radius int) {
+	fmt.Println()
+	if len(stops) == 0 {
+		fmt.Printf("%sNo stops found within %dm%s\n\n", yellow, radius, reset)
+		return
+	}
+
+	fmt.Printf("%s%s Stops within %dm of %.4f, %.4f %s\n\n", bold, white, radius, lat, lon, reset)
+
+	nameWidth := fitWidth(40, 29)
+	for _, stop := range stops {
+		direction := compassPoint(stop.BearingFrom(lat, lon))
+		fmt.Printf("  %s%s%s %5dm %-2s  %s~%d min walk%s\n",
+			cyan, fitColumn(stop.Name, nameWidth), reset,
+			int(stop.Distance), direction,
+			gray, walkingMinutes(stop.Distance), reset)
+
+		var lines []string
+		for _, line := range stop.Lines {
+			lines = append(lines, getLineColor(line.ID)+" "+line.Name+" "+reset)
+		}
+		fmt.Printf("  %sID: %s%s  [%s]  %s\n\n", gray, stop.ID, reset, strings.Join(stop.Modes, ", "), strings.Join(lines, " "))
+	}
+}
+
+func walkingMinutes(distance float64) int {
+	mins := int(math.Ceil(distance / walkingSpeed))
+	if mins < 1 {
+		return 1
+	}
+	return mins
+}
+
+func compassPoint(bearing float64) string {
+	points := []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+	return points[int(math.Round(bearing/45))%len(points)]
+}
+
+func shortStationName(name string) string {
+	name = strings.TrimSuffix(name, " Underground Station")
+	name = strings.TrimSuffix(name, " Rail Station")
+	name = strings.TrimSuffix(name, " DLR Station")
+	return name
+}
+
+// interchangeLines returns the lines other than lineID serving a stop.
+func interchangeLines(stop tfl.RouteStop, lineID string) []tfl.LineIdentifier {
+	var others []tfl.LineIdentifier
+	for _, line := range stop.Lines {
+		if line.ID != lineID {
+			others = append(others, line)
+		}
+	}
+	return others
+}
+
+// PrintRouteSequence draws the line as a strip map: stops shared by several
+// routes appear once, with branches splitting from and rejoining the trunk.
+func PrintRouteSequence(seq *tfl.RouteSequence) {
+	fmt.Println()
+	if len(seq.OrderedLineRoutes) == 0 {
+		fmt.Printf("%sNo route found for %s%s\n\n", yellow, seq.LineName, reset)
+		return
+	}
+
+	lineCol := getLineColor(seq.LineID)
+	fmt.Printf("%s%s%s %s%s%s\n", lineCol, formatLineName(displayLineName(seq.LineID, seq.LineName)), reset, gray, seq.Direction, reset)
+	for _, route := range seq.OrderedLineRoutes {
+		fmt.Printf("  %s%s%s\n", gray, route.Name, reset)
+	}
+	fmt.Println()
+
+	stops := seq.Stops()
+	routes := make([][]string, 0, len(seq.OrderedLineRoutes))
+	termini := make(map[string]bool)
+	for _, route := range seq.OrderedLineRoutes {
+		if len(route.NaptanIDs) == 0 {
+			continue
+		}
+		routes = append(routes, route.NaptanIDs)
+		termini[route.NaptanIDs[0]] = true
+		termini[route.NaptanIDs[len(route.NaptanIDs)-1]] = true
+	}
+
+	marker := func(id string) string {
+		if len(interchangeLines(stops[id], seq.LineID)) > 0 {
+			return "<>"
+		}
+		return "o"
+	}
+	rows := stripMap(routes, marker)
+	graphWidth := 0
+	for _, row := range rows {
+		if w := displayWidth(row.graph); w > graphWidth {
+			graphWidth = w
+		}
+	}
+
+	for _, row := range rows {
+		if row.stop == "" {
+			fmt.Printf("  %s  %s %s\n", lineCol, reset, row.graph)
+			continue
+		}
+
+		stop, ok := stops[row.stop]
+		name := row.stop
+		if ok {
+			name = shortStationName(stop.Name)
+		}
+		if termini[row.stop] {
+			name = bold + name + reset
+		}
+
+		var badges []string
+		for _, line := range interchangeLines(stop, seq.LineID) {
+			badges = append(badges, getLineColor(line.ID)+" "+line.Name+" "+reset)
+		}
+
+		fmt.Printf("  %s  %s %s %s  %s\n", lineCol, reset, padWidth(row.graph, graphWidth), name, strings.Join(badges, " "))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,125 @@
+package display
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"tfl/internal/tfl"
+)
+
+func TestPrintArrivalsVerbose(t *testing.T) {
+	width := terminalWidth
+	defer func() { terminalWidth = width }()
+	terminalWidth = func() int { return 100 }
+
+	arrivals := []tfl.Arrival{
+		{
+			LineID: "central", LineName: "Central", DestinationName: "Epping",
+			PlatformName: "Eastbound - Platform 1", VehicleID: "202", CurrentLocation: "At Liverpool Street",
+			TimeToStation: 120, ExpectedArrival: time.Now().Add(2 * time.Minute),
+		},
+		{
+			LineID: "central", LineName: "Central", DestinationName: "Hainault",
+			PlatformName: "Eastbound - Platform 1", TimeToStation: 300, ExpectedArrival: time.Now().Add(5 * time.Minute),
+		},
+	}
+
+	tests := []struct {
+		verbose bool
+		want    []string
+		notWant []string
+	}{
+		{false, nil, []string{"vehicle"}},
+		{true, []string{"vehicle 202  At Liverpool Street"}, []string{"vehicle -"}},
+	}
+
+	for _, tt := range tests {
+		out := captureStdout(t, func() { PrintArrivals(arrivals, "Bethnal Green", tt.verbose) })
+		for _, s := range tt.want {
+			if !strings.Contains(out, s) {
+				t.Errorf("verbose %v: output missing %q:\n%s", tt.verbose, s, out)
+			}
+		}
+		for _, s := range tt.notWant {
+			if strings.Contains(out, s) {
+				t.Errorf("verbose %v: output has %q:\n%s", tt.verbose, s, out)
+			}
+		}
+	}
+}
+
+func TestPrintScheduledArrival(t *testing.T) {
+	width := terminalWidth
+	defer func() { terminalWidth = width }()
+
+	arr := tfl.Arrival{
+		LineID: "central", LineName: "Central", DestinationName: "Epping",
+		PlatformName: "Eastbound - Platform 1", TimeToStation: 2400,
+		ExpectedArrival: time.Now().Add(40 * time.Minute), Source: tfl.SourceTimetable,
+	}
+
+	for _, cols := range []int{100, 40} {
+		terminalWidth = func() int { return cols }
+		out := captureStdout(t, func() { PrintArrivals([]tfl.Arrival{arr}, "Bethnal Green", false) })
+		if strings.Contains(out, "Platform") {
+			t.Errorf("width %d: scheduled arrival shows a platform:\n%s", cols, out)
+		}
+		if !strings.Contains(out, dim) {
+			t.Errorf("width %d: scheduled arrival is not dimmed:\n%q", cols, out)
+		}
+		if cols >= compactWidth && !strings.Contains(out, "sched") {
+			t.Errorf("width %d: scheduled arrival has no sched marker:\n%s", cols, out)
+		}
+	}
+
+	arr.Source = tfl.SourceRealtime
+	terminalWidth = func() int { return 100 }
+	out := captureStdout(t, func() { PrintArrivals([]tfl.Arrival{arr}, "Bethnal Green", false) })
+	if strings.Contains(out, "sched") || !strings.Contains(out, "Eastbound - Platform 1") {
+		t.Errorf("live arrival:\n%s", out)
+	}
+}
+
+func TestWalkingMinutes(t *testing.T) {
+	tests := []struct {
+		distance float64
+		want     int
+	}{
+		{0, 1},
+		{1, 1},
+		{80, 1},
+		{81, 2},
+		{800, 10},
+	}
+
+	for _, tt := range tests {
+		if got := walkingMinutes(tt.distance); got != tt.want {
+			t.Errorf("walkingMinutes(%v) = %d, want %d", tt.distance, got, tt.want)
+		}
+	}
+}
+
+func TestCompassPoint(t *testing.T) {
+	tests := []struct {
+		bearing float64
+		want    string
+	}{
+		{0, "N"},
+		{22.4, "N"},
+		{22.5, "NE"},
+		{90, "E"},
+		{180, "S"},
+		{270, "W"},
+		{337.4, "NW"},
+		{337.5, "N"},
+		{359.9, "N"},
+		{360, "N"},
+	}
+
+	for _, tt := range tests {
+		if got := compassPoint(tt.bearing); got != tt.want {
+			t.Errorf("compassPoint(%v) = %q, want %q", tt.bearing, got, tt.want)
+		}
+	}
+}
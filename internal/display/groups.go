@@ -0,0 +1,106 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"tfl/internal/tfl"
+)
+
+// GroupBy values accepted by GroupArrivals.
+var GroupByFields = []string{"platform", "line", "direction"}
+
+// ArrivalGroup is a section of a grouped departures board.
+type ArrivalGroup struct {
+	Key      string
+	Title    string
+	Arrivals []tfl.Arrival
+}
+
+// GroupArrivals splits arrivals into groups by platform, line or
+// direction, keeping at most perGroup arrivals in each (0 for no limit).
+// Groups are ordered by title, with numbers compared by value so that
+// "Platform 2" sorts before "Platform 10".
+func GroupArrivals(arrivals []tfl.Arrival, by string, perGroup int) ([]ArrivalGroup, error) {
+	var keyOf func(tfl.Arrival) (key, title string)
+	switch by {
+	case "platform":
+		keyOf = func(a tfl.Arrival) (string, string) {
+			if a.PlatformName == "" {
+				return "", "Platform unknown"
+			}
+			return a.PlatformName, a.PlatformName
+		}
+	case "line":
+		keyOf = func(a tfl.Arrival) (string, string) {
+			return a.LineID, displayLineName(a.LineID, a.LineName)
+		}
+	case "direction":
+		keyOf = func(a tfl.Arrival) (string, string) {
+			d := strings.ToLower(a.Direction)
+			if d == "" {
+				return "", "Direction unknown"
+			}
+			return d, strings.ToUpper(d[:1]) + d[1:]
+		}
+	default:
+		return nil, fmt.Errorf("unknown group '%s' (use %s)", by, strings.Join(GroupByFields, ", "))
+	}
+
+	var groups []ArrivalGroup
+	index := make(map[string]int)
+	for _, a := range arrivals {
+		key, title := keyOf(a)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, ArrivalGroup{Key: key, Title: title})
+		}
+		if perGroup > 0 && len(groups[i].Arrivals) >= perGroup {
+			continue
+		}
+		groups[i].Arrivals = append(groups[i].Arrivals, a)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return naturalLess(groups[i].Title, groups[j].Title)
+	})
+	return groups, nil
+}
+
+// naturalLess compares strings case-insensitively, treating runs of digits
+// as numbers.
+func naturalLess(a, b string) bool {
+	ra, rb := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	i, j := 0, 0
+	for i < len(ra) && j < len(rb) {
+		if unicode.IsDigit(ra[i]) && unicode.IsDigit(rb[j]) {
+			si := i
+			for i < len(ra) && unicode.IsDigit(ra[i]) {
+				i++
+			}
+			sj := j
+			for j < len(rb) && unicode.IsDigit(rb[j]) {
+				j++
+			}
+			na := strings.TrimLeft(string(ra[si:i]), "0")
+			nb := strings.TrimLeft(string(rb[sj:j]), "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			continue
+		}
+		if ra[i] != rb[j] {
+			return ra[i] < rb[j]
+		}
+		i++
+		j++
+	}
+	return len(ra)-i < len(rb)-j
+}
@@ -0,0 +1,91 @@
+package display
+
+import (
+	"encoding/json"
+	"testing"
+
+	"tfl/internal/tfl"
+)
+
+func TestGroupArrivals(t *testing.T) {
+	arrivals := []tfl.Arrival{
+		{LineID: "victoria", LineName: "Victoria", PlatformName: "Platform 10", Direction: "outbound"},
+		{LineID: "northern", LineName: "Northern", PlatformName: "Platform 2", Direction: "inbound"},
+		{LineID: "victoria", LineName: "Victoria", PlatformName: "Platform 10", Direction: "outbound"},
+		{LineID: "victoria", LineName: "Victoria", PlatformName: "Platform 10", Direction: "outbound"},
+		{LineID: "northern", LineName: "Northern", Direction: ""},
+	}
+
+	tests := []struct {
+		by       string
+		perGroup int
+		titles   []string
+		counts   []int
+	}{
+		{"platform", 0, []string{"Platform 2", "Platform 10", "Platform unknown"}, []int{1, 3, 1}},
+		{"platform", 2, []string{"Platform 2", "Platform 10", "Platform unknown"}, []int{1, 2, 1}},
+		{"line", 0, []string{"Northern", "Victoria"}, []int{2, 3}},
+		{"direction", 1, []string{"Direction unknown", "Inbound", "Outbound"}, []int{1, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			groups, err := GroupArrivals(arrivals, tt.by, tt.perGroup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(groups) != len(tt.titles) {
+				t.Fatalf("got %d groups, want %d", len(groups), len(tt.titles))
+			}
+			for i, g := range groups {
+				if g.Title != tt.titles[i] || len(g.Arrivals) != tt.counts[i] {
+					t.Errorf("group %d = %q with %d arrivals, want %q with %d", i, g.Title, len(g.Arrivals), tt.titles[i], tt.counts[i])
+				}
+			}
+		})
+	}
+
+	if _, err := GroupArrivals(arrivals, "colour", 0); err == nil {
+		t.Error("expected error for unknown group")
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Platform 2", "Platform 10", true},
+		{"Platform 10", "Platform 2", false},
+		{"platform 1", "Platform 1a", true},
+		{"Eastbound", "Westbound", true},
+		{"Platform 02", "Platform 2", false},
+	}
+
+	for _, tt := range tests {
+		if got := naturalLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("naturalLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDeparturesOutputGroups(t *testing.T) {
+	arrivals := []tfl.Arrival{
+		{LineID: "victoria", PlatformName: "Platform 5"},
+		{LineID: "northern", PlatformName: "Platform 1"},
+		{LineID: "victoria", PlatformName: "Platform 5"},
+	}
+
+	out := NewDeparturesOutput(arrivals, DeparturesContext{GroupBy: "platform", PerGroup: 1})
+	if out.GroupBy != "platform" || len(out.Groups) != 2 || out.Count != 3 || len(out.Arrivals) != 3 {
+		data, _ := json.Marshal(out)
+		t.Fatalf("unexpected output: %s", data)
+	}
+	if out.Groups[0].Title != "Platform 1" || out.Groups[1].Count != 1 {
+		t.Errorf("groups = %+v", out.Groups)
+	}
+	// arrivals stays the flat list in its original order.
+	if out.Arrivals[0].LineID != "victoria" || out.Arrivals[1].LineID != "northern" {
+		t.Errorf("arrivals reordered by grouping: %+v", out.Arrivals)
+	}
+}
@@ -0,0 +1,163 @@
+package display
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"tfl/internal/tfl"
+)
+
+const icsTimeFormat = "20060102T150405Z"
+
+// plannedSeverities are the status severities TfL uses for closures that
+// are arranged in advance, as opposed to live delays and suspensions.
+var plannedSeverities = map[int]bool{
+	1:  true, // Closed
+	4:  true, // Planned Closure
+	5:  true, // Part Closure
+	11: true, // Part Closed
+}
+
+// plannedWork is the disruption category for engineering works.
+const plannedWork = "PlannedWork"
+
+type CalendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+}
+
+// PlannedWorksEvents returns one event per validity period of each planned
+// works status, so each closure window gets its own calendar entry. A
+// status counts as planned works if its reason is that of a PlannedWork
+// disruption, or if it has a planned-closure severity and the period is
+// not the current one, which keeps live delays out. Planned work with no
+// matching status has no known window and is left out. Windows reported
+// more than once are merged by UID.
+func PlannedWorksEvents(statuses []tfl.LineStatus, disruptions []tfl.Disruption) []CalendarEvent {
+	planned := make(map[string]bool)
+	for _, d := range disruptions {
+		if d.Category == plannedWork {
+			planned[strings.TrimSpace(d.Description)] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	var events []CalendarEvent
+
+	for _, line := range statuses {
+		for _, status := range line.LineStatuses {
+			plannedReason := status.Reason != "" && planned[strings.TrimSpace(status.Reason)]
+			if !plannedReason && !plannedSeverities[status.StatusSeverity] {
+				continue
+			}
+			for _, period := range status.ValidityPeriods {
+				if !period.ToDate.After(period.FromDate) {
+					continue
+				}
+				if period.IsNow && !plannedReason {
+					continue
+				}
+
+				sum := sha1.Sum([]byte(line.ID + "|" + period.FromDate.UTC().Format(icsTimeFormat) + "|" +
+					period.ToDate.UTC().Format(icsTimeFormat) + "|" + status.Reason))
+				uid := hex.EncodeToString(sum[:8]) + "@tfl-cli"
+				if seen[uid] {
+					continue
+				}
+				seen[uid] = true
+
+				events = append(events, CalendarEvent{
+					UID:         uid,
+					Summary:     fmt.Sprintf("%s: %s", line.Name, status.StatusSeverityDescription),
+					Description: status.Reason,
+					Start:       period.FromDate,
+					End:         period.ToDate,
+				})
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+	return events
+}
+
+// WriteCalendar writes the events as an RFC 5545 calendar.
+func WriteCalendar(w io.Writer, events []CalendarEvent, now time.Time) error {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICSLine(s))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//tfl-cli//Planned Works//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:TfL Planned Works")
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID)
+		line("DTSTAMP:" + now.UTC().Format(icsTimeFormat))
+		line("DTSTART:" + e.Start.UTC().Format(icsTimeFormat))
+		line("DTEND:" + e.End.UTC().Format(icsTimeFormat))
+		line("SUMMARY:" + escapeICSText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escapeICSText(e.Description))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func PrintCalendar(events []CalendarEvent) {
+	if err := WriteCalendar(os.Stdout, events, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing calendar: %v\n", err)
+	}
+}
+
+func escapeICSText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// foldICSLine splits content lines longer than 75 octets, continuing them
+// on lines that start with a space, without splitting UTF-8 sequences.
+func foldICSLine(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := utf8.RuneLen(r)
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
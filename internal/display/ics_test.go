@@ -0,0 +1,101 @@
+package display
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"tfl/internal/tfl"
+)
+
+func TestPlannedWorksEvents(t *testing.T) {
+	raw := `[
+		{"id": "central", "name": "Central", "lineStatuses": [
+			{"statusSeverity": 4, "statusSeverityDescription": "Planned Closure", "reason": "No service Saturday",
+			 "validityPeriods": [
+				{"fromDate": "2026-10-24T04:30:00Z", "toDate": "2026-10-26T01:30:00Z"},
+				{"fromDate": "2026-10-31T04:30:00Z", "toDate": "2026-11-02T01:30:00Z"}]},
+			{"statusSeverity": 4, "statusSeverityDescription": "Planned Closure", "reason": "No service Saturday",
+			 "validityPeriods": [{"fromDate": "2026-10-24T04:30:00Z", "toDate": "2026-10-26T01:30:00Z"}]}]},
+		{"id": "victoria", "name": "Victoria", "lineStatuses": [
+			{"statusSeverity": 10, "statusSeverityDescription": "Good Service",
+			 "validityPeriods": [{"fromDate": "2026-10-18T00:00:00Z", "toDate": "2026-10-19T00:00:00Z"}]}]},
+		{"id": "jubilee", "name": "Jubilee", "lineStatuses": [
+			{"statusSeverity": 9, "statusSeverityDescription": "Minor Delays", "reason": "Signal failure",
+			 "validityPeriods": [{"fromDate": "2026-10-18T08:00:00Z", "toDate": "2026-10-18T10:00:00Z", "isNow": true}]},
+			{"statusSeverity": 5, "statusSeverityDescription": "Part Closure", "reason": "No service Stanmore - Wembley Park",
+			 "validityPeriods": [{"fromDate": "2026-10-18T04:30:00Z", "toDate": "2026-10-19T01:00:00Z", "isNow": true}]}]},
+		{"id": "district", "name": "District", "lineStatuses": [
+			{"statusSeverity": 9, "statusSeverityDescription": "Minor Delays", "reason": "Reduced service for track works",
+			 "validityPeriods": [{"fromDate": "2026-11-07T04:30:00Z", "toDate": "2026-11-08T23:00:00Z"}]}]}
+	]`
+	var statuses []tfl.LineStatus
+	if err := json.Unmarshal([]byte(raw), &statuses); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	disruptions := []tfl.Disruption{
+		{Category: "RealTime", Description: "Signal failure"},
+		{Category: "PlannedWork", Description: "Reduced service for track works"},
+	}
+
+	events := PlannedWorksEvents(statuses, disruptions)
+	var summaries []string
+	for _, e := range events {
+		summaries = append(summaries, e.Summary)
+	}
+	// The duplicate Central window is merged, good service and the live
+	// delay and current part closure on the Jubilee are skipped, and the
+	// District delay is included as planned work.
+	want := []string{"Central: Planned Closure", "Central: Planned Closure", "District: Minor Delays"}
+	if strings.Join(summaries, "|") != strings.Join(want, "|") {
+		t.Fatalf("PlannedWorksEvents() = %q, want %q", summaries, want)
+	}
+	if !events[0].Start.Before(events[1].Start) {
+		t.Errorf("events not in start order: %+v", events)
+	}
+}
+
+func TestWriteCalendar(t *testing.T) {
+	start := time.Date(2026, 10, 24, 4, 30, 0, 0, time.UTC)
+	events := []CalendarEvent{{
+		UID:         "abc@tfl-cli",
+		Summary:     "Central: Planned Closure",
+		Description: "No service between White City and Ealing Broadway; replacement buses operate, please allow extra time.",
+		Start:       start,
+		End:         start.Add(45 * time.Hour),
+	}}
+
+	var out strings.Builder
+	if err := WriteCalendar(&out, events, start); err != nil {
+		t.Fatalf("WriteCalendar() error: %v", err)
+	}
+	got := out.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTART:20261024T043000Z\r\n",
+		"DTEND:20261026T013000Z\r\n",
+		"SUMMARY:Central: Planned Closure\r\n",
+		`DESCRIPTION:No service between White City and Ealing Broadway\; replacement` + "\r\n  buses",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("calendar missing %q:\n%s", want, got)
+		}
+	}
+
+	for _, l := range strings.Split(got, "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("line longer than 75 octets: %q", l)
+		}
+	}
+}
+
+func TestEscapeICSText(t *testing.T) {
+	got := escapeICSText("a, b; c\\d\ne")
+	want := `a\, b\; c\\d\ne`
+	if got != want {
+		t.Errorf("escapeICSText() = %q, want %q", got, want)
+	}
+}
@@ -3,27 +3,91 @@ package display
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"tfl/internal/tfl"
 )
 
+// DeparturesSchemaVersion is the version of DeparturesOutput. Version 1
+// is the original shape, still available through DeparturesOutputV1.
+const DeparturesSchemaVersion = 2
+
 type ArrivalJSON struct {
 	Line            string `json:"line"`
 	LineID          string `json:"line_id"`
 	Destination     string `json:"destination"`
+	Towards         string `json:"towards,omitempty"`
+	Direction       string `json:"direction,omitempty"`
 	Platform        string `json:"platform,omitempty"`
 	TimeToStation   int    `json:"time_to_station_seconds"`
 	MinutesAway     int    `json:"minutes_away"`
 	ExpectedArrival string `json:"expected_arrival"`
+	VehicleID       string `json:"vehicle_id,omitempty"`
+	CurrentLocation string `json:"current_location,omitempty"`
+	Source          string `json:"source"`
+}
+
+type StopCandidateJSON struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
 type DeparturesOutput struct {
-	Station  string        `json:"station"`
+	SchemaVersion int                 `json:"schema_version"`
+	Station       string              `json:"station"`
+	StopID        string              `json:"stop_id"`
+	Query         string              `json:"query,omitempty"`
+	Candidates    []StopCandidateJSON `json:"candidates,omitempty"`
+	Source        string              `json:"source"`
+	FetchedAt     string              `json:"fetched_at"`
+	GroupBy       string              `json:"group_by,omitempty"`
+	Groups        []ArrivalGroupJSON  `json:"groups,omitempty"`
+	Arrivals      []ArrivalJSON       `json:"arrivals"`
+	Count         int                 `json:"count"`
+}
+
+type ArrivalGroupJSON struct {
+	Key      string        `json:"key"`
+	Title    string        `json:"title"`
 	Arrivals []ArrivalJSON `json:"arrivals"`
 	Count    int           `json:"count"`
 }
 
+// DeparturesContext describes how a departures result was obtained: the
+// stop it was resolved to, the search results considered, and whether the
+// arrivals are real-time predictions or timetable entries. GroupBy and
+// PerGroup group the arrivals as GroupArrivals does.
+type DeparturesContext struct {
+	Station    string
+	StopID     string
+	Query      string
+	Candidates []tfl.StopPoint
+	Source     string
+	FetchedAt  time.Time
+	GroupBy    string
+	PerGroup   int
+}
+
+type ArrivalJSONV1 struct {
+	Line            string `json:"line"`
+	LineID          string `json:"line_id"`
+	Destination     string `json:"destination"`
+	Platform        string `json:"platform,omitempty"`
+	TimeToStation   int    `json:"time_to_station_seconds"`
+	MinutesAway     int    `json:"minutes_away"`
+	ExpectedArrival string `json:"expected_arrival"`
+	VehicleID       string `json:"vehicle_id,omitempty"`
+	CurrentLocation string `json:"current_location,omitempty"`
+}
+
+type DeparturesOutputV1 struct {
+	Station  string          `json:"station"`
+	Arrivals []ArrivalJSONV1 `json:"arrivals"`
+	Count    int             `json:"count"`
+}
+
 type LineStatusJSON struct {
 	Line     string `json:"line"`
 	LineID   string `json:"line_id"`
@@ -59,23 +123,175 @@ type StopPointsOutput struct {
 	Count    int             `json:"count"`
 }
 
-func printJSON(v interface{}) {
-	enc := json.NewEncoder(os.Stdout)
+type NearbyStopJSON struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Distance    int      `json:"distance_metres"`
+	WalkMinutes int      `json:"walk_minutes"`
+	Bearing     int      `json:"bearing_degrees"`
+	Direction   string   `json:"direction"`
+	Modes       []string `json:"modes"`
+	Lines       []string `json:"lines"`
+}
+
+type NearbyOutput struct {
+	Latitude  float64          `json:"latitude"`
+	Longitude float64          `json:"longitude"`
+	Radius    int              `json:"radius_metres"`
+	Stops     []NearbyStopJSON `json:"stops"`
+	Count     int              `json:"count"`
+}
+
+type RouteStopJSON struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Interchanges []string `json:"interchanges,omitempty"`
+}
+
+type RouteJSON struct {
+	Name        string          `json:"name"`
+	ServiceType string          `json:"service_type,omitempty"`
+	Stops       []RouteStopJSON `json:"stops"`
+}
+
+type BranchJSON struct {
+	BranchID      int      `json:"branch_id"`
+	NextBranchIDs []int    `json:"next_branch_ids"`
+	PrevBranchIDs []int    `json:"prev_branch_ids"`
+	Stops         []string `json:"stops"`
+}
+
+type RouteOutput struct {
+	Line      string       `json:"line"`
+	LineID    string       `json:"line_id"`
+	Direction string       `json:"direction"`
+	Routes    []RouteJSON  `json:"routes"`
+	Branches  []BranchJSON `json:"branches"`
+}
+
+type VehicleStopJSON struct {
+	Station         string `json:"station"`
+	MinutesAway     int    `json:"minutes_away"`
+	ExpectedArrival string `json:"expected_arrival"`
+}
+
+type VehicleOutput struct {
+	VehicleID       string            `json:"vehicle_id"`
+	Line            string            `json:"line,omitempty"`
+	LineID          string            `json:"line_id,omitempty"`
+	Destination     string            `json:"destination,omitempty"`
+	CurrentLocation string            `json:"current_location,omitempty"`
+	Stops           []VehicleStopJSON `json:"stops"`
+	Count           int               `json:"count"`
+}
+
+type StationLineJSON struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type StationInfoOutput struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Lines    []StationLineJSON `json:"lines"`
+	Children []string          `json:"children,omitempty"`
+}
+
+// CheckOutput is the result of validating the API key.
+type CheckOutput struct {
+	Valid   bool   `json:"valid"`
+	Message string `json:"message"`
+	Error   string `json:"error,omitempty"`
+}
+
+// WriteJSON writes v as indented JSON, the shape used by every command.
+func WriteJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
-	if err := enc.Encode(v); err != nil {
+	return enc.Encode(v)
+}
+
+func printJSON(v interface{}) {
+	if err := WriteJSON(os.Stdout, v); err != nil {
 		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
 	}
 }
 
-func PrintArrivalsJSON(arrivals []tfl.Arrival, stationName string) {
+func PrintArrivalsJSON(arrivals []tfl.Arrival, ctx DeparturesContext) {
+	printJSON(NewDeparturesOutput(arrivals, ctx))
+}
+
+func NewDeparturesOutput(arrivals []tfl.Arrival, ctx DeparturesContext) DeparturesOutput {
 	output := DeparturesOutput{
+		SchemaVersion: DeparturesSchemaVersion,
+		Station:       ctx.Station,
+		StopID:        ctx.StopID,
+		Query:         ctx.Query,
+		Source:        ctx.Source,
+		FetchedAt:     ctx.FetchedAt.Format(time.RFC3339),
+		Arrivals:      make([]ArrivalJSON, 0, len(arrivals)),
+		Count:         len(arrivals),
+	}
+
+	for _, stop := range ctx.Candidates {
+		output.Candidates = append(output.Candidates, StopCandidateJSON{ID: stop.ID, Name: stop.Name})
+	}
+
+	for _, arr := range arrivals {
+		output.Arrivals = append(output.Arrivals, newArrivalJSON(arr))
+	}
+
+	// Grouping only adds the groups view; arrivals stays the flat,
+	// time-ordered list.
+	if ctx.GroupBy != "" {
+		groups, err := GroupArrivals(arrivals, ctx.GroupBy, ctx.PerGroup)
+		if err == nil {
+			output.GroupBy = ctx.GroupBy
+			for _, g := range groups {
+				group := ArrivalGroupJSON{Key: g.Key, Title: g.Title, Arrivals: make([]ArrivalJSON, 0, len(g.Arrivals)), Count: len(g.Arrivals)}
+				for _, arr := range g.Arrivals {
+					group.Arrivals = append(group.Arrivals, newArrivalJSON(arr))
+				}
+				output.Groups = append(output.Groups, group)
+			}
+		}
+	}
+
+	return output
+}
+
+func newArrivalJSON(arr tfl.Arrival) ArrivalJSON {
+	return ArrivalJSON{
+		Line:            arr.LineName,
+		LineID:          arr.LineID,
+		Destination:     arr.DestinationName,
+		Towards:         arr.Towards,
+		Direction:       arr.Direction,
+		Platform:        arr.PlatformName,
+		TimeToStation:   arr.TimeToStation,
+		MinutesAway:     arr.TimeToStation / 60,
+		ExpectedArrival: arr.ExpectedArrival.Format(time.RFC3339),
+		VehicleID:       arr.VehicleID,
+		CurrentLocation: arr.CurrentLocation,
+		Source:          arr.Source,
+	}
+}
+
+// PrintArrivalsJSONV1 prints departures in the schema version 1 shape, with
+// local HH:MM arrival times and no stop or source details.
+func PrintArrivalsJSONV1(arrivals []tfl.Arrival, stationName string) {
+	printJSON(NewDeparturesOutputV1(arrivals, stationName))
+}
+
+func NewDeparturesOutputV1(arrivals []tfl.Arrival, stationName string) DeparturesOutputV1 {
+	output := DeparturesOutputV1{
 		Station:  stationName,
-		Arrivals: make([]ArrivalJSON, 0, len(arrivals)),
+		Arrivals: make([]ArrivalJSONV1, 0, len(arrivals)),
 		Count:    len(arrivals),
 	}
 
 	for _, arr := range arrivals {
-		output.Arrivals = append(output.Arrivals, ArrivalJSON{
+		output.Arrivals = append(output.Arrivals, ArrivalJSONV1{
 			Line:            arr.LineName,
 			LineID:          arr.LineID,
 			Destination:     arr.DestinationName,
@@ -83,13 +299,19 @@ func PrintArrivalsJSON(arrivals []tfl.Arrival, stationName string) {
 			TimeToStation:   arr.TimeToStation,
 			MinutesAway:     arr.TimeToStation / 60,
 			ExpectedArrival: arr.ExpectedArrival.Local().Format("15:04"),
+			VehicleID:       arr.VehicleID,
+			CurrentLocation: arr.CurrentLocation,
 		})
 	}
 
-	printJSON(output)
+	return output
 }
 
 func PrintLineStatusesJSON(statuses []tfl.LineStatus) {
+	printJSON(NewStatusOutput(statuses))
+}
+
+func NewStatusOutput(statuses []tfl.LineStatus) StatusOutput {
 	output := StatusOutput{
 		Lines: make([]LineStatusJSON, 0, len(statuses)),
 		Count: len(statuses),
@@ -106,10 +328,14 @@ func PrintLineStatusesJSON(statuses []tfl.LineStatus) {
 		})
 	}
 
-	printJSON(output)
+	return output
 }
 
 func PrintDisruptionsJSON(disruptions []tfl.Disruption) {
+	printJSON(NewDisruptionsOutput(disruptions))
+}
+
+func NewDisruptionsOutput(disruptions []tfl.Disruption) DisruptionsOutput {
 	output := DisruptionsOutput{
 		Disruptions: make([]DisruptionJSON, 0, len(disruptions)),
 		Count:       len(disruptions),
@@ -122,10 +348,14 @@ func PrintDisruptionsJSON(disruptions []tfl.Disruption) {
 		})
 	}
 
-	printJSON(output)
+	return output
 }
 
 func PrintStopPointsJSON(stops []tfl.StopPoint) {
+	printJSON(NewStopPointsOutput(stops))
+}
+
+func NewStopPointsOutput(stops []tfl.StopPoint) StopPointsOutput {
 	output := StopPointsOutput{
 		Stations: make([]StopPointJSON, 0, len(stops)),
 		Count:    len(stops),
@@ -140,5 +370,132 @@ func PrintStopPointsJSON(stops []tfl.StopPoint) {
 		})
 	}
 
-	printJSON(output)
+	return output
+}
+
+func PrintNearbyStopsJSON(stops []tfl.NearbyStopPoint, lat, lon float64, radius int) {
+	printJSON(NewNearbyOutput(stops, lat, lon, radius))
+}
+
+func NewNearbyOutput(stops []tfl.NearbyStopPoint, lat, lon float64, radius int) NearbyOutput {
+	output := NearbyOutput{
+		Latitude:  lat,
+		Longitude: lon,
+		Radius:    radius,
+		Stops:     make([]NearbyStopJSON, 0, len(stops)),
+		Count:     len(stops),
+	}
+
+	for _, stop := range stops {
+		bearing := stop.BearingFrom(lat, lon)
+		lines := make([]string, 0, len(stop.Lines))
+		for _, line := range stop.Lines {
+			lines = append(lines, line.Name)
+		}
+		output.Stops = append(output.Stops, NearbyStopJSON{
+			ID:          stop.ID,
+			Name:        stop.Name,
+			Distance:    int(stop.Distance),
+			WalkMinutes: walkingMinutes(stop.Distance),
+			Bearing:     int(bearing),
+			Direction:   compassPoint(bearing),
+			Modes:       stop.Modes,
+			Lines:       lines,
+		})
+	}
+
+	return output
+}
+
+func PrintRouteSequenceJSON(seq *tfl.RouteSequence) {
+	printJSON(NewRouteOutput(seq))
+}
+
+func NewRouteOutput(seq *tfl.RouteSequence) RouteOutput {
+	output := RouteOutput{
+		Line:      seq.LineName,
+		LineID:    seq.LineID,
+		Direction: seq.Direction,
+		Routes:    make([]RouteJSON, 0, len(seq.OrderedLineRoutes)),
+		Branches:  make([]BranchJSON, 0, len(seq.StopPointSequences)),
+	}
+
+	stops := seq.Stops()
+	for _, route := range seq.OrderedLineRoutes {
+		r := RouteJSON{
+			Name:        route.Name,
+			ServiceType: route.ServiceType,
+			Stops:       make([]RouteStopJSON, 0, len(route.NaptanIDs)),
+		}
+		for _, id := range route.NaptanIDs {
+			stop := stops[id]
+			rs := RouteStopJSON{ID: id, Name: shortStationName(stop.Name)}
+			for _, line := range interchangeLines(stop, seq.LineID) {
+				rs.Interchanges = append(rs.Interchanges, line.Name)
+			}
+			r.Stops = append(r.Stops, rs)
+		}
+		output.Routes = append(output.Routes, r)
+	}
+
+	for _, branch := range seq.StopPointSequences {
+		b := BranchJSON{
+			BranchID:      branch.BranchID,
+			NextBranchIDs: branch.NextBranchIDs,
+			PrevBranchIDs: branch.PrevBranchIDs,
+			Stops:         make([]string, 0, len(branch.StopPoints)),
+		}
+		for _, sp := range branch.StopPoints {
+			b.Stops = append(b.Stops, sp.ID)
+		}
+		output.Branches = append(output.Branches, b)
+	}
+
+	return output
+}
+
+func PrintVehicleArrivalsJSON(arrivals []tfl.Arrival, vehicleID string) {
+	printJSON(NewVehicleOutput(arrivals, vehicleID))
+}
+
+func NewVehicleOutput(arrivals []tfl.Arrival, vehicleID string) VehicleOutput {
+	output := VehicleOutput{
+		VehicleID: vehicleID,
+		Stops:     make([]VehicleStopJSON, 0, len(arrivals)),
+		Count:     len(arrivals),
+	}
+
+	if len(arrivals) > 0 {
+		output.Line = arrivals[0].LineName
+		output.LineID = arrivals[0].LineID
+		output.Destination = arrivals[0].DestinationName
+		output.CurrentLocation = arrivals[0].CurrentLocation
+	}
+
+	for _, arr := range arrivals {
+		output.Stops = append(output.Stops, VehicleStopJSON{
+			Station:         shortStationName(arr.StationName),
+			MinutesAway:     arr.TimeToStation / 60,
+			ExpectedArrival: arr.ExpectedArrival.Local().Format("15:04"),
+		})
+	}
+
+	return output
+}
+
+func NewStationInfoOutput(detail *tfl.StopPointDetail) StationInfoOutput {
+	output := StationInfoOutput{
+		ID:    detail.ID,
+		Name:  detail.Name,
+		Lines: make([]StationLineJSON, 0, len(detail.Lines)),
+	}
+
+	for _, line := range detail.Lines {
+		output.Lines = append(output.Lines, StationLineJSON{ID: line.ID, Name: line.Name})
+	}
+	for _, child := range detail.Children {
+		output.Children = append(output.Children, child.ID)
+	}
+
+	return output
 }
@@ -0,0 +1,31 @@
+package display
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"tfl/internal/tfl"
+)
+
+func TestArrivalJSONSource(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{tfl.SourceRealtime, `"source":"realtime"`},
+		{tfl.SourceTimetable, `"source":"timetable"`},
+	}
+
+	for _, tt := range tests {
+		arr := tfl.Arrival{LineID: "central", LineName: "Central", DestinationName: "Epping", ExpectedArrival: time.Now(), Source: tt.source}
+		out, err := json.Marshal(newArrivalJSON(arr))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(out), tt.want) {
+			t.Errorf("%s arrival: %s, want %s", tt.source, out, tt.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package display
+
+import "strings"
+
+// LineInfo describes a TfL service: its line ID, display name, mode and
+// brand colour.
+type LineInfo struct {
+	ID       string
+	Name     string
+	Mode     string
+	Color    string
+	DarkText bool
+}
+
+// Lines is the registry of current TfL services. Bus routes are not listed
+// individually; they take the bus colour from modeColors.
+var Lines = []LineInfo{
+	{"bakerloo", "Bakerloo", "tube", "#b26300", false},
+	{"central", "Central", "tube", "#dc241f", false},
+	{"circle", "Circle", "tube", "#ffd300", true},
+	{"district", "District", "tube", "#007d32", false},
+	{"hammersmith-city", "Hammersmith & City", "tube", "#f4a9be", true},
+	{"jubilee", "Jubilee", "tube", "#a1a5a7", true},
+	{"metropolitan", "Metropolitan", "tube", "#9b0058", false},
+	{"northern", "Northern", "tube", "#000000", false},
+	{"piccadilly", "Piccadilly", "tube", "#003688", false},
+	{"victoria", "Victoria", "tube", "#00a0e2", false},
+	{"waterloo-city", "Waterloo & City", "tube", "#93ceba", true},
+	{"elizabeth", "Elizabeth line", "elizabeth-line", "#6b3fa0", false},
+	{"dlr", "DLR", "dlr", "#00afad", false},
+	{"liberty", "Liberty", "overground", "#5d6061", false},
+	{"lioness", "Lioness", "overground", "#faa61a", true},
+	{"mildmay", "Mildmay", "overground", "#0077ad", false},
+	{"suffragette", "Suffragette", "overground", "#5bbd72", true},
+	{"weaver", "Weaver", "overground", "#823a62", false},
+	{"windrush", "Windrush", "overground", "#ed1b00", false},
+	{"london-overground", "London Overground", "overground", "#ef7b10", true},
+	{"tram", "Tram", "tram", "#84b817", true},
+	{"london-cable-car", "IFS Cloud Cable Car", "cable-car", "#e21836", false},
+	{"rb1", "RB1", "river-bus", "#0099cc", false},
+	{"rb1x", "RB1X", "river-bus", "#0099cc", false},
+	{"rb2", "RB2", "river-bus", "#0099cc", false},
+	{"rb4", "RB4", "river-bus", "#0099cc", false},
+	{"rb5", "RB5", "river-bus", "#0099cc", false},
+	{"rb6", "RB6", "river-bus", "#0099cc", false},
+}
+
+// modeColors colour lines that are not in the registry by their mode.
+var modeColors = map[string]lineColor{
+	"bus":        {rgb{220, 36, 31}, false},
+	"river-bus":  {rgb{0, 153, 204}, false},
+	"overground": {rgb{239, 123, 16}, true},
+	"tram":       {rgb{132, 184, 23}, true},
+}
+
+// LookupLine returns the registry entry for a line ID.
+func LookupLine(id string) (LineInfo, bool) {
+	for _, l := range Lines {
+		if l.ID == id {
+			return l, true
+		}
+	}
+	return LineInfo{}, false
+}
+
+// LookupLineByName returns the registry entry whose display name matches
+// name, ignoring case and a trailing " line".
+func LookupLineByName(name string) (LineInfo, bool) {
+	name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), " line")
+	for _, l := range Lines {
+		if strings.TrimSuffix(strings.ToLower(l.Name), " line") == name {
+			return l, true
+		}
+	}
+	return LineInfo{}, false
+}
+
+// displayLineName is the registry's display name for a line, or fallback
+// (the name from the API) for lines it doesn't know.
+func displayLineName(id, fallback string) string {
+	if l, ok := LookupLine(id); ok {
+		return l.Name
+	}
+	return fallback
+}
+
+func registryColors() map[string]lineColor {
+	colors := make(map[string]lineColor, len(Lines))
+	for _, l := range Lines {
+		c, err := parseHex(l.Color)
+		if err != nil {
+			panic("display: bad colour for line " + l.ID)
+		}
+		colors[l.ID] = lineColor{c, l.DarkText}
+	}
+	return colors
+}
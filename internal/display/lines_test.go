@@ -0,0 +1,65 @@
+package display
+
+import "testing"
+
+func TestLineRegistry(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, l := range Lines {
+		if seen[l.ID] {
+			t.Errorf("duplicate line %s", l.ID)
+		}
+		seen[l.ID] = true
+		if _, err := parseHex(l.Color); err != nil {
+			t.Errorf("line %s: %v", l.ID, err)
+		}
+		if l.Name == "" || l.Mode == "" {
+			t.Errorf("line %s: missing name or mode", l.ID)
+		}
+	}
+
+	for _, id := range []string{"liberty", "lioness", "mildmay", "suffragette", "weaver", "windrush", "tram", "london-cable-car", "rb1"} {
+		if lookupLineColor(id) == defaultLineColor {
+			t.Errorf("line %s has no colour", id)
+		}
+	}
+}
+
+func TestLookupModeLineColor(t *testing.T) {
+	tests := []struct {
+		id, mode string
+		want     lineColor
+	}{
+		{"central", "bus", lineColors["central"]},
+		{"73", "bus", modeColors["bus"]},
+		{"unknown", "", defaultLineColor},
+	}
+
+	for _, tt := range tests {
+		if got := lookupModeLineColor(tt.id, tt.mode); got != tt.want {
+			t.Errorf("lookupModeLineColor(%q, %q) = %+v, want %+v", tt.id, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestLookupLineByName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Elizabeth line", "elizabeth"},
+		{"elizabeth", "elizabeth"},
+		{"hammersmith & city", "hammersmith-city"},
+		{"Ifs Cloud Cable Car", "london-cable-car"},
+		{"Windrush", "windrush"},
+	}
+
+	for _, tt := range tests {
+		l, ok := LookupLineByName(tt.name)
+		if !ok || l.ID != tt.want {
+			t.Errorf("LookupLineByName(%q) = %q, %v; want %q", tt.name, l.ID, ok, tt.want)
+		}
+	}
+	if _, ok := LookupLineByName("Hogwarts Express"); ok {
+		t.Error("expected no match")
+	}
+}
@@ -0,0 +1,91 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"tfl/internal/tfl"
+)
+
+// The record types below are the JSON item types with the context that a
+// standalone line needs: when it was fetched and for which station or query.
+
+type ArrivalRecord struct {
+	FetchedAt string `json:"fetched_at"`
+	Station   string `json:"station"`
+	StopID    string `json:"stop_id"`
+	Query     string `json:"query,omitempty"`
+	ArrivalJSON
+}
+
+type LineStatusRecord struct {
+	FetchedAt string `json:"fetched_at"`
+	LineStatusJSON
+}
+
+type DisruptionRecord struct {
+	FetchedAt string `json:"fetched_at"`
+	DisruptionJSON
+}
+
+type StopPointRecord struct {
+	FetchedAt string `json:"fetched_at"`
+	Query     string `json:"query"`
+	StopPointJSON
+}
+
+// printNDJSON writes each record as compact JSON on its own line.
+func printNDJSON[T any](records []T) {
+	enc := json.NewEncoder(os.Stdout)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			return
+		}
+	}
+}
+
+// PrintArrivalsNDJSON prints a record per arrival, with the same station,
+// stop ID, query and fetch time as the departures JSON.
+func PrintArrivalsNDJSON(arrivals []tfl.Arrival, ctx DeparturesContext) {
+	records := make([]ArrivalRecord, 0, len(arrivals))
+	for _, arr := range arrivals {
+		records = append(records, ArrivalRecord{
+			FetchedAt:   ctx.FetchedAt.Format(time.RFC3339),
+			Station:     ctx.Station,
+			StopID:      ctx.StopID,
+			Query:       ctx.Query,
+			ArrivalJSON: newArrivalJSON(arr),
+		})
+	}
+	printNDJSON(records)
+}
+
+func PrintLineStatusesNDJSON(statuses []tfl.LineStatus, fetchedAt time.Time) {
+	output := NewStatusOutput(statuses)
+	records := make([]LineStatusRecord, 0, len(output.Lines))
+	for _, l := range output.Lines {
+		records = append(records, LineStatusRecord{FetchedAt: fetchedAt.Format(time.RFC3339), LineStatusJSON: l})
+	}
+	printNDJSON(records)
+}
+
+func PrintDisruptionsNDJSON(disruptions []tfl.Disruption, fetchedAt time.Time) {
+	output := NewDisruptionsOutput(disruptions)
+	records := make([]DisruptionRecord, 0, len(output.Disruptions))
+	for _, d := range output.Disruptions {
+		records = append(records, DisruptionRecord{FetchedAt: fetchedAt.Format(time.RFC3339), DisruptionJSON: d})
+	}
+	printNDJSON(records)
+}
+
+func PrintStopPointsNDJSON(stops []tfl.StopPoint, query string, fetchedAt time.Time) {
+	output := NewStopPointsOutput(stops)
+	records := make([]StopPointRecord, 0, len(output.Stations))
+	for _, s := range output.Stations {
+		records = append(records, StopPointRecord{FetchedAt: fetchedAt.Format(time.RFC3339), Query: query, StopPointJSON: s})
+	}
+	printNDJSON(records)
+}
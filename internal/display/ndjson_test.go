@@ -0,0 +1,112 @@
+package display
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"tfl/internal/tfl"
+)
+
+func TestNDJSON(t *testing.T) {
+	fetchedAt := time.Date(2024, 3, 4, 8, 15, 0, 0, time.UTC)
+	arrivals := []tfl.Arrival{
+		{LineID: "central", LineName: "Central", DestinationName: "Epping", TimeToStation: 120, ExpectedArrival: fetchedAt.Add(2 * time.Minute)},
+		{LineID: "central", LineName: "Central", DestinationName: "Hainault", TimeToStation: 300, ExpectedArrival: fetchedAt.Add(5 * time.Minute)},
+	}
+	ctx := DeparturesContext{Station: "Bethnal Green Underground Station", StopID: "940GZZLUBLG", Query: "bethnal", FetchedAt: fetchedAt}
+	statuses := []tfl.LineStatus{
+		{ID: "central", Name: "Central", LineStatuses: []tfl.Status{{StatusSeverity: 10, StatusSeverityDescription: "Good Service"}}},
+	}
+	disruptions := []tfl.Disruption{{Category: "RealTime", Description: "Minor delays"}}
+	stops := []tfl.StopPoint{{ID: "940GZZLUBLG", Name: "Bethnal Green Underground Station"}}
+
+	tests := []struct {
+		name  string
+		print func(empty bool)
+		lines int
+		want  map[string]string
+	}{
+		{
+			name: "arrivals",
+			print: func(empty bool) {
+				if empty {
+					PrintArrivalsNDJSON(nil, ctx)
+				} else {
+					PrintArrivalsNDJSON(arrivals, ctx)
+				}
+			},
+			lines: 2,
+			want:  map[string]string{"station": ctx.Station, "stop_id": ctx.StopID, "query": "bethnal"},
+		},
+		{
+			name: "statuses",
+			print: func(empty bool) {
+				if empty {
+					PrintLineStatusesNDJSON(nil, fetchedAt)
+				} else {
+					PrintLineStatusesNDJSON(statuses, fetchedAt)
+				}
+			},
+			lines: 1,
+		},
+		{
+			name: "disruptions",
+			print: func(empty bool) {
+				if empty {
+					PrintDisruptionsNDJSON(nil, fetchedAt)
+				} else {
+					PrintDisruptionsNDJSON(disruptions, fetchedAt)
+				}
+			},
+			lines: 1,
+		},
+		{
+			name: "stop points",
+			print: func(empty bool) {
+				if empty {
+					PrintStopPointsNDJSON(nil, "bethnal", fetchedAt)
+				} else {
+					PrintStopPointsNDJSON(stops, "bethnal", fetchedAt)
+				}
+			},
+			lines: 1,
+			want:  map[string]string{"query": "bethnal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := captureStdout(t, func() { tt.print(false) })
+			if !strings.HasSuffix(out, "\n") {
+				t.Fatalf("output doesn't end in a newline: %q", out)
+			}
+			lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+			if len(lines) != tt.lines {
+				t.Fatalf("got %d lines, want %d:\n%s", len(lines), tt.lines, out)
+			}
+			for _, line := range lines {
+				var record map[string]interface{}
+				if err := json.Unmarshal([]byte(line), &record); err != nil {
+					t.Fatalf("line is not a JSON object: %v\n%s", err, line)
+				}
+				if strings.HasPrefix(line, "{\n") || strings.Contains(line, "  ") {
+					t.Errorf("line is not compact: %s", line)
+				}
+				if got := record["fetched_at"]; got != "2024-03-04T08:15:00Z" {
+					t.Errorf("fetched_at = %v", got)
+				}
+				for field, want := range tt.want {
+					if got := record[field]; got != want {
+						t.Errorf("%s = %v, want %q", field, got, want)
+					}
+				}
+			}
+
+			if out := captureStdout(t, func() { tt.print(true) }); out != "" {
+				t.Errorf("empty input printed %q", out)
+			}
+		})
+	}
+}
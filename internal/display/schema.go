@@ -0,0 +1,32 @@
+package display
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed schema/*.json
+var schemaFS embed.FS
+
+// SchemaNames lists the outputs that have a published JSON Schema.
+func SchemaNames() []string {
+	entries, _ := schemaFS.ReadDir("schema")
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Schema returns the JSON Schema document for the named output.
+func Schema(name string) ([]byte, error) {
+	data, err := schemaFS.ReadFile(path.Join("schema", name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("no schema named %q (available: %s)", name, strings.Join(SchemaNames(), ", "))
+	}
+	return data, nil
+}
@@ -0,0 +1,194 @@
+package display
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"tfl/internal/tfl"
+)
+
+func TestSchemasAreValidJSON(t *testing.T) {
+	names := SchemaNames()
+	if len(names) == 0 {
+		t.Fatal("no schemas embedded")
+	}
+	for _, name := range names {
+		data, err := Schema(name)
+		if err != nil {
+			t.Fatalf("Schema(%q): %v", name, err)
+		}
+		var doc map[string]interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Errorf("schema %q is not valid JSON: %v", name, err)
+		}
+	}
+}
+
+func TestSchemaUnknown(t *testing.T) {
+	if _, err := Schema("nope"); err == nil {
+		t.Error("expected error for unknown schema")
+	}
+}
+
+// schemaSamples returns an output for each schema with every optional
+// field filled in, so that fields missing from a schema are caught.
+func schemaSamples() map[string]interface{} {
+	now := time.Date(2024, 3, 4, 8, 15, 0, 0, time.UTC)
+	arrival := tfl.Arrival{
+		LineID: "central", LineName: "Central", DestinationName: "Epping", Towards: "Epping",
+		Direction: "outbound", PlatformName: "Eastbound - Platform 1", VehicleID: "202",
+		CurrentLocation: "At Liverpool Street", StationName: "Bethnal Green Underground Station",
+		TimeToStation: 120, ExpectedArrival: now.Add(2 * time.Minute), Source: tfl.SourceRealtime,
+	}
+	arrivals := []tfl.Arrival{arrival}
+	ctx := DeparturesContext{
+		Station: "Bethnal Green Underground Station", StopID: "940GZZLUBLG", Query: "bethnal",
+		Candidates: []tfl.StopPoint{{ID: "940GZZLUBLG", Name: "Bethnal Green Underground Station"}},
+		Source:     tfl.SourceRealtime, FetchedAt: now, GroupBy: "platform", PerGroup: 3,
+	}
+	statuses := []tfl.LineStatus{{ID: "central", Name: "Central", LineStatuses: []tfl.Status{
+		{StatusSeverity: 9, StatusSeverityDescription: "Minor Delays", Reason: "Signal failure"},
+	}}}
+	disruptions := []tfl.Disruption{{Category: "RealTime", CategoryDescription: "RealTime", Description: "Minor delays"}}
+	stops := []tfl.StopPoint{{ID: "940GZZLUBLG", Name: "Bethnal Green Underground Station", Zone: "2", Modes: []string{"tube"}}}
+	nearby := []tfl.NearbyStopPoint{{
+		ID: "940GZZLUBLG", Name: "Bethnal Green Underground Station", Distance: 120, Lat: 51.527, Lon: -0.055,
+		Modes: []string{"tube"}, Lines: []tfl.LineIdentifier{{ID: "central", Name: "Central"}},
+	}}
+	seq := &tfl.RouteSequence{
+		LineID: "central", LineName: "Central", Direction: "outbound",
+		StopPointSequences: []tfl.StopPointSequence{{
+			BranchID: 0, NextBranchIDs: []int{1}, PrevBranchIDs: []int{},
+			StopPoints: []tfl.RouteStop{
+				{ID: "940GZZLUBLG", Name: "Bethnal Green Underground Station"},
+				{ID: "940GZZLULVT", Name: "Liverpool Street Underground Station", Lines: []tfl.LineIdentifier{{ID: "circle", Name: "Circle"}}},
+			},
+		}},
+		OrderedLineRoutes: []tfl.OrderedRoute{{Name: "Epping - West Ruislip", NaptanIDs: []string{"940GZZLUBLG", "940GZZLULVT"}, ServiceType: "Regular"}},
+	}
+	detail := &tfl.StopPointDetail{
+		ID: "940GZZLUOXC", Name: "Oxford Circus Underground Station",
+		Lines:    []tfl.Line{{ID: "central", Name: "Central"}},
+		Children: []tfl.StopPointDetail{{ID: "9400ZZLUOXC1"}},
+	}
+	fetchedAt := now.Format(time.RFC3339)
+
+	return map[string]interface{}{
+		"check":         CheckOutput{Valid: false, Message: "API key validation failed", Error: "API returned status 401"},
+		"departures":    NewDeparturesOutput(arrivals, ctx),
+		"departures-v1": NewDeparturesOutputV1(arrivals, ctx.Station),
+		"disruptions":   NewDisruptionsOutput(disruptions),
+		"line":          NewRouteOutput(seq),
+		"nearby":        NewNearbyOutput(nearby, 51.5265, -0.0553, 800),
+		"search":        NewStopPointsOutput(stops),
+		"station-info":  NewStationInfoOutput(detail),
+		"status":        NewStatusOutput(statuses),
+		"track":         NewVehicleOutput(arrivals, "202"),
+
+		"departures-ndjson": ArrivalRecord{
+			FetchedAt: fetchedAt, Station: ctx.Station, StopID: ctx.StopID, Query: ctx.Query,
+			ArrivalJSON: newArrivalJSON(arrival),
+		},
+		"disruptions-ndjson": DisruptionRecord{FetchedAt: fetchedAt, DisruptionJSON: NewDisruptionsOutput(disruptions).Disruptions[0]},
+		"search-ndjson":      StopPointRecord{FetchedAt: fetchedAt, Query: "bethnal", StopPointJSON: NewStopPointsOutput(stops).Stations[0]},
+		"status-ndjson":      LineStatusRecord{FetchedAt: fetchedAt, LineStatusJSON: NewStatusOutput(statuses).Lines[0]},
+	}
+}
+
+func TestSchemasMatchOutputs(t *testing.T) {
+	samples := schemaSamples()
+	for _, name := range SchemaNames() {
+		sample, ok := samples[name]
+		if !ok {
+			t.Errorf("no sample output for schema %q", name)
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			checkSchemaFields(t, name, sample)
+		})
+	}
+	for name := range samples {
+		if _, err := Schema(name); err != nil {
+			t.Errorf("sample %q has no schema", name)
+		}
+	}
+}
+
+func TestSchemaFieldsEmptyDepartures(t *testing.T) {
+	checkSchemaFields(t, "departures", NewDeparturesOutput(nil, DeparturesContext{Station: "X", StopID: "940GZZX"}))
+}
+
+// schemaNode is the part of a JSON Schema that describes object fields.
+type schemaNode struct {
+	Ref        string                 `json:"$ref"`
+	Required   []string               `json:"required"`
+	Properties map[string]*schemaNode `json:"properties"`
+	Items      *schemaNode            `json:"items"`
+	Defs       map[string]*schemaNode `json:"$defs"`
+}
+
+// checkSchemaFields checks that v, as JSON, has every field the named
+// schema requires and no field the schema doesn't describe, in nested
+// objects and arrays as well as at the top level.
+func checkSchemaFields(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	data, err := Schema(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var root schemaNode
+	if err := json.Unmarshal(data, &root); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(out, &value); err != nil {
+		t.Fatal(err)
+	}
+	checkSchemaNode(t, name, &root, &root, value)
+}
+
+func checkSchemaNode(t *testing.T, path string, root, node *schemaNode, value interface{}) {
+	t.Helper()
+	if node.Ref != "" {
+		def, ok := root.Defs[strings.TrimPrefix(node.Ref, "#/$defs/")]
+		if !ok {
+			t.Errorf("%s: unresolved $ref %q", path, node.Ref)
+			return
+		}
+		node = def
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if node.Properties == nil {
+			return
+		}
+		for _, req := range node.Required {
+			if _, ok := v[req]; !ok {
+				t.Errorf("%s: output missing required field %q", path, req)
+			}
+		}
+		for field, fv := range v {
+			prop, ok := node.Properties[field]
+			if !ok {
+				t.Errorf("%s: output field %q not described by schema", path, field)
+				continue
+			}
+			checkSchemaNode(t, path+"."+field, root, prop, fv)
+		}
+	case []interface{}:
+		if node.Items == nil {
+			return
+		}
+		for _, item := range v {
+			checkSchemaNode(t, path+"[]", root, node.Items, item)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package display
+
+import "strings"
+
+// stripLaneWidth is the number of columns each branch takes in a strip map.
+const stripLaneWidth = 3
+
+// stripRow is one line of a strip map: a stop on one of the branches, or,
+// when stop is empty, a connector where branches split or join.
+type stripRow struct {
+	graph string
+	stop  string
+}
+
+// stripMap lays out routes (each an ordered list of stop IDs) as a single
+// strip map in the style of git log --graph, so stops shared by several
+// routes appear once and branches split from and rejoin the trunk. marker
+// returns the symbol drawn for a stop.
+func stripMap(routes [][]string, marker func(id string) string) []stripRow {
+	order := routeOrder(routes)
+
+	var rows []stripRow
+	var lanes []string
+	for _, id := range order.stops {
+		var cols []int
+		for i, next := range lanes {
+			if next == id {
+				cols = append(cols, i)
+			}
+		}
+		if len(cols) == 0 {
+			lanes = append(lanes, id)
+			cols = []int{len(lanes) - 1}
+		}
+
+		// Branches that end here join the leftmost one, right to left so
+		// the remaining column numbers stay valid.
+		for j := len(cols) - 1; j > 0; j-- {
+			rows = append(rows, stripRow{graph: joinRow(len(lanes), cols[j])})
+			lanes = append(lanes[:cols[j]], lanes[cols[j]+1:]...)
+		}
+		c := cols[0]
+
+		rows = append(rows, stripRow{graph: stopRow(len(lanes), c, marker(id)), stop: id})
+
+		next := order.next[id]
+		if len(next) == 0 {
+			if c < len(lanes)-1 {
+				rows = append(rows, stripRow{graph: endRow(len(lanes), c)})
+			}
+			lanes = append(lanes[:c], lanes[c+1:]...)
+			continue
+		}
+		lanes[c] = next[0]
+		for m, branch := range next[1:] {
+			at := c + 1 + m
+			lanes = append(lanes[:at], append([]string{branch}, lanes[at:]...)...)
+			rows = append(rows, stripRow{graph: splitRow(len(lanes), at)})
+		}
+	}
+	return rows
+}
+
+// stripOrder is the stops of a set of routes in drawing order, with the
+// stops that directly follow each one.
+type stripOrder struct {
+	stops []string
+	next  map[string][]string
+}
+
+// routeOrder merges routes into one graph and orders its stops so each
+// comes after everything before it on any route. Among the stops that could
+// come next, the one seen first in routes wins, so a branch is drawn in full
+// before the next one starts. A link that would make a loop is dropped.
+func routeOrder(routes [][]string) stripOrder {
+	seen := make(map[string]int)
+	var ids []string
+	next := make(map[string][]string)
+	prev := make(map[string]int)
+
+	reaches := func(from, to string) bool {
+		visited := make(map[string]bool)
+		stack := []string{from}
+		for len(stack) > 0 {
+			id := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if id == to {
+				return true
+			}
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+			stack = append(stack, next[id]...)
+		}
+		return false
+	}
+	link := func(from, to string) {
+		for _, n := range next[from] {
+			if n == to {
+				return
+			}
+		}
+		if from == to || reaches(to, from) {
+			return
+		}
+		next[from] = append(next[from], to)
+		prev[to]++
+	}
+
+	for _, route := range routes {
+		for i, id := range route {
+			if _, ok := seen[id]; !ok {
+				seen[id] = len(ids)
+				ids = append(ids, id)
+			}
+			if i > 0 {
+				link(route[i-1], id)
+			}
+		}
+	}
+
+	order := stripOrder{next: next}
+	placed := make(map[string]bool)
+	for len(order.stops) < len(ids) {
+		best := ""
+		for _, id := range ids {
+			if !placed[id] && prev[id] == 0 {
+				best = id
+				break
+			}
+		}
+		placed[best] = true
+		order.stops = append(order.stops, best)
+		for _, n := range next[best] {
+			prev[n]--
+		}
+	}
+	return order
+}
+
+// stopRow draws lanes with marker in column col.
+func stopRow(lanes, col int, marker string) string {
+	var b strings.Builder
+	for i := 0; i < lanes; i++ {
+		cell := "|"
+		if i == col {
+			cell = marker
+		}
+		b.WriteString(padWidth(cell, stripLaneWidth))
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// joinRow draws column col joining the one to its left, with the columns
+// after it moving left to close the gap.
+func joinRow(lanes, col int) string {
+	row := []byte(strings.Repeat(" ", lanes*stripLaneWidth))
+	for i := 0; i < lanes; i++ {
+		if i < col {
+			row[i*stripLaneWidth] = '|'
+		} else {
+			row[i*stripLaneWidth-2] = '/'
+		}
+	}
+	return strings.TrimRight(string(row), " ")
+}
+
+// endRow draws column col ending, with the columns after it moving left.
+func endRow(lanes, col int) string {
+	row := []byte(strings.Repeat(" ", lanes*stripLaneWidth))
+	for i := 0; i < lanes; i++ {
+		switch {
+		case i < col:
+			row[i*stripLaneWidth] = '|'
+		case i > col:
+			row[i*stripLaneWidth-2] = '/'
+		}
+	}
+	return strings.TrimRight(string(row), " ")
+}
+
+// splitRow draws a new column col branching off the one to its left, with
+// the columns after it moving right to make room.
+func splitRow(lanes, col int) string {
+	row := []byte(strings.Repeat(" ", lanes*stripLaneWidth))
+	for i := 0; i < lanes; i++ {
+		if i < col {
+			row[i*stripLaneWidth] = '|'
+		} else {
+			row[i*stripLaneWidth-2] = '\\'
+		}
+	}
+	return strings.TrimRight(string(row), " ")
+}
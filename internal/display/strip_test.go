@@ -0,0 +1,115 @@
+package display
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripMap(t *testing.T) {
+	marker := func(id string) string {
+		if strings.HasPrefix(id, "*") {
+			return "<>"
+		}
+		return "o"
+	}
+
+	tests := []struct {
+		name   string
+		routes [][]string
+		want   []string
+	}{
+		{
+			name:   "single route",
+			routes: [][]string{{"A", "B", "C"}},
+			want:   []string{"o A", "o B", "o C"},
+		},
+		{
+			// Like the Northern line's Bank and Charing Cross branches.
+			name: "split and join",
+			routes: [][]string{
+				{"Edgware", "*Camden", "*Euston", "Bank", "*Kennington", "Morden"},
+				{"Edgware", "*Camden", "*Euston", "Charing Cross", "*Kennington", "Morden"},
+			},
+			want: []string{
+				"o Edgware",
+				"<> *Camden",
+				"<> *Euston",
+				"|\\",
+				"o  | Bank",
+				"|  o Charing Cross",
+				"|/",
+				"<> *Kennington",
+				"o Morden",
+			},
+		},
+		{
+			// Like the Central line's Hainault loop, with a short working
+			// that adds nothing new.
+			name: "loop",
+			routes: [][]string{
+				{"Epping", "Woodford", "South Woodford", "Leytonstone", "Ealing Broadway"},
+				{"Hainault", "Newbury Park", "Leytonstone", "Ealing Broadway"},
+				{"Woodford", "Grange Hill", "Hainault"},
+				{"Woodford", "South Woodford", "Leytonstone"},
+			},
+			want: []string{
+				"o Epping",
+				"o Woodford",
+				"|\\",
+				"o  | South Woodford",
+				"|  o Grange Hill",
+				"|  o Hainault",
+				"|  o Newbury Park",
+				"|/",
+				"o Leytonstone",
+				"o Ealing Broadway",
+			},
+		},
+		{
+			name: "branch ends",
+			routes: [][]string{
+				{"Stratford", "North Acton", "West Ruislip"},
+				{"Stratford", "North Acton", "Ealing Broadway"},
+			},
+			want: []string{
+				"o Stratford",
+				"o North Acton",
+				"|\\",
+				"o  | West Ruislip",
+				" /",
+				"o Ealing Broadway",
+			},
+		},
+		{
+			name: "branch starts",
+			routes: [][]string{
+				{"High Barnet", "Finchley Central", "Camden"},
+				{"Mill Hill East", "Finchley Central", "Camden"},
+			},
+			want: []string{
+				"o High Barnet",
+				"|  o Mill Hill East",
+				"|/",
+				"o Finchley Central",
+				"o Camden",
+			},
+		},
+		{
+			name:   "a link back is dropped",
+			routes: [][]string{{"A", "B", "C"}, {"C", "A"}},
+			want:   []string{"o A", "o B", "o C"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, row := range stripMap(tt.routes, marker) {
+				got = append(got, strings.TrimRight(row.graph+" "+row.stop, " "))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package display
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"tfl/internal/tfl"
+)
+
+// Table is the tabular form of a command's output. Column names match the
+// JSON field names and are stable, so scripts can rely on them.
+//
+// TextColumns is the subset shown by the text output, used by the document
+// formats (markdown and html) when no columns are chosen. LineIDs, when
+// set, holds the line of each row so renderers can colour it.
+type Table struct {
+	Columns     []string
+	TextColumns []string
+	Rows        [][]string
+	LineIDs     []string
+}
+
+func ArrivalsTable(arrivals []tfl.Arrival, stationName string) Table {
+	t := Table{
+		Columns:     []string{"station", "line", "line_id", "destination", "platform", "minutes_away", "expected_arrival", "vehicle_id", "current_location", "source"},
+		TextColumns: []string{"line", "expected_arrival", "minutes_away", "destination", "platform"},
+	}
+	for _, arr := range arrivals {
+		t.LineIDs = append(t.LineIDs, arr.LineID)
+		t.Rows = append(t.Rows, []string{
+			stationName,
+			arr.LineName,
+			arr.LineID,
+			arr.DestinationName,
+			arr.PlatformName,
+			strconv.Itoa(arr.TimeToStation / 60),
+			arr.ExpectedArrival.Local().Format("15:04"),
+			arr.VehicleID,
+			arr.CurrentLocation,
+			arr.Source,
+		})
+	}
+	return t
+}
+
+func LineStatusesTable(statuses []tfl.LineStatus) Table {
+	t := Table{
+		Columns:     []string{"line", "line_id", "status", "severity", "reason"},
+		TextColumns: []string{"line", "status", "reason"},
+	}
+	for _, line := range statuses {
+		status := line.LineStatuses[0]
+		t.LineIDs = append(t.LineIDs, line.ID)
+		t.Rows = append(t.Rows, []string{
+			line.Name,
+			line.ID,
+			status.StatusSeverityDescription,
+			strconv.Itoa(status.StatusSeverity),
+			status.Reason,
+		})
+	}
+	return t
+}
+
+func DisruptionsTable(disruptions []tfl.Disruption) Table {
+	t := Table{
+		Columns:     []string{"category", "description"},
+		TextColumns: []string{"category", "description"},
+	}
+	for _, d := range disruptions {
+		t.Rows = append(t.Rows, []string{d.CategoryDescription, d.Description})
+	}
+	return t
+}
+
+func StopPointsTable(stops []tfl.StopPoint) Table {
+	t := Table{
+		Columns:     []string{"id", "name", "zone", "modes"},
+		TextColumns: []string{"name", "zone", "modes", "id"},
+	}
+	for _, stop := range stops {
+		t.Rows = append(t.Rows, []string{stop.ID, stop.Name, stop.Zone, strings.Join(stop.Modes, ",")})
+	}
+	return t
+}
+
+func NearbyStopsTable(stops []tfl.NearbyStopPoint, lat, lon float64) Table {
+	t := Table{
+		Columns:     []string{"id", "name", "distance_metres", "walk_minutes", "bearing_degrees", "direction", "modes", "lines"},
+		TextColumns: []string{"name", "distance_metres", "direction", "walk_minutes", "id", "lines"},
+	}
+	for _, stop := range NewNearbyOutput(stops, lat, lon, 0).Stops {
+		t.Rows = append(t.Rows, []string{
+			stop.ID,
+			stop.Name,
+			strconv.Itoa(stop.Distance),
+			strconv.Itoa(stop.WalkMinutes),
+			strconv.Itoa(stop.Bearing),
+			stop.Direction,
+			strings.Join(stop.Modes, ","),
+			strings.Join(stop.Lines, ","),
+		})
+	}
+	return t
+}
+
+// RouteSequenceTable has a row per stop on each route, in order.
+func RouteSequenceTable(seq *tfl.RouteSequence) Table {
+	t := Table{
+		Columns:     []string{"line", "line_id", "direction", "route", "position", "id", "name", "interchanges"},
+		TextColumns: []string{"route", "position", "name", "interchanges"},
+	}
+	output := NewRouteOutput(seq)
+	for _, route := range output.Routes {
+		for i, stop := range route.Stops {
+			t.LineIDs = append(t.LineIDs, output.LineID)
+			t.Rows = append(t.Rows, []string{
+				output.Line,
+				output.LineID,
+				output.Direction,
+				route.Name,
+				strconv.Itoa(i + 1),
+				stop.ID,
+				stop.Name,
+				strings.Join(stop.Interchanges, ","),
+			})
+		}
+	}
+	return t
+}
+
+func VehicleArrivalsTable(arrivals []tfl.Arrival, vehicleID string) Table {
+	t := Table{
+		Columns:     []string{"vehicle_id", "line", "line_id", "destination", "station", "minutes_away", "expected_arrival"},
+		TextColumns: []string{"expected_arrival", "minutes_away", "station"},
+	}
+	output := NewVehicleOutput(arrivals, vehicleID)
+	for _, stop := range output.Stops {
+		t.LineIDs = append(t.LineIDs, output.LineID)
+		t.Rows = append(t.Rows, []string{
+			vehicleID,
+			output.Line,
+			output.LineID,
+			output.Destination,
+			stop.Station,
+			strconv.Itoa(stop.MinutesAway),
+			stop.ExpectedArrival,
+		})
+	}
+	return t
+}
+
+// Select returns a table with only the named columns, in the given order.
+func (t Table) Select(columns []string) (Table, error) {
+	index := make(map[string]int, len(t.Columns))
+	for i, c := range t.Columns {
+		index[c] = i
+	}
+
+	picked := make([]int, 0, len(columns))
+	for _, c := range columns {
+		i, ok := index[c]
+		if !ok {
+			return Table{}, fmt.Errorf("unknown column '%s' (available: %s)", c, strings.Join(t.Columns, ", "))
+		}
+		picked = append(picked, i)
+	}
+
+	out := Table{Columns: columns, TextColumns: columns, LineIDs: t.LineIDs}
+	for _, row := range t.Rows {
+		selected := make([]string, len(picked))
+		for j, i := range picked {
+			selected[j] = row[i]
+		}
+		out.Rows = append(out.Rows, selected)
+	}
+	return out, nil
+}
+
+// WriteCSV writes the table with a header row, quoted per RFC 4180.
+func WriteCSV(w io.Writer, t Table) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Columns); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// WriteTSV writes the table with a header row. Fields are never quoted;
+// tabs and line breaks inside them become spaces so every record stays on
+// one line for cut and awk.
+func WriteTSV(w io.Writer, t Table) error {
+	clean := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+	writeRow := func(row []string) error {
+		fields := make([]string, len(row))
+		for i, f := range row {
+			fields[i] = clean.Replace(f)
+		}
+		_, err := fmt.Fprintln(w, strings.Join(fields, "\t"))
+		return err
+	}
+
+	if err := writeRow(t.Columns); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		if err := writeRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteMarkdown writes the table as a GitHub-flavoured Markdown table.
+func WriteMarkdown(w io.Writer, t Table) error {
+	clean := strings.NewReplacer("|", "\\|", "\r\n", " ", "\n", " ", "\r", " ")
+	writeRow := func(row []string) error {
+		fields := make([]string, len(row))
+		for i, f := range row {
+			fields[i] = clean.Replace(f)
+		}
+		_, err := fmt.Fprintf(w, "| %s |\n", strings.Join(fields, " | "))
+		return err
+	}
+
+	if err := writeRow(t.Columns); err != nil {
+		return err
+	}
+	separators := make([]string, len(t.Columns))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	if err := writeRow(separators); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		if err := writeRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteHTML writes the table as a standalone HTML table. The line column is
+// rendered as a badge in the line's brand colour.
+func WriteHTML(w io.Writer, t Table) error {
+	var b strings.Builder
+	b.WriteString("<table>\n  <thead>\n    <tr>")
+	for _, c := range t.Columns {
+		fmt.Fprintf(&b, "<th>%s</th>", html.EscapeString(c))
+	}
+	b.WriteString("</tr>\n  </thead>\n  <tbody>\n")
+
+	for i, row := range t.Rows {
+		b.WriteString("    <tr>")
+		for j, field := range row {
+			cell := html.EscapeString(field)
+			if t.Columns[j] == "line" && i < len(t.LineIDs) {
+				cell = lineBadge(t.LineIDs[i], field)
+			}
+			fmt.Fprintf(&b, "<td>%s</td>", cell)
+		}
+		b.WriteString("</tr>\n")
+	}
+
+	b.WriteString("  </tbody>\n</table>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func lineBadge(lineID, name string) string {
+	c := lookupLineColor(lineID)
+	fg := "#ffffff"
+	if c.darkText {
+		fg = "#000000"
+	}
+	return fmt.Sprintf(`<span style="background:%s;color:%s;padding:0 0.4em;border-radius:3px">%s</span>`,
+		c.bg.hex(), fg, html.EscapeString(name))
+}
+
+// PrintTable writes t to stdout in format (csv, tsv, markdown or html),
+// restricted to columns if any are given. Markdown and HTML default to the
+// columns of the text output.
+func PrintTable(t Table, format string, columns []string) error {
+	if len(columns) == 0 && (format == "markdown" || format == "html") {
+		columns = t.TextColumns
+	}
+	if len(columns) > 0 {
+		var err error
+		if t, err = t.Select(columns); err != nil {
+			return err
+		}
+	}
+
+	switch format {
+	case "tsv":
+		return WriteTSV(os.Stdout, t)
+	case "markdown":
+		return WriteMarkdown(os.Stdout, t)
+	case "html":
+		return WriteHTML(os.Stdout, t)
+	default:
+		return WriteCSV(os.Stdout, t)
+	}
+}
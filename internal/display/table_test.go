@@ -0,0 +1,140 @@
+package display
+
+import (
+	"strings"
+	"testing"
+
+	"tfl/internal/tfl"
+)
+
+var testTable = Table{
+	Columns: []string{"line", "destination", "platform"},
+	Rows: [][]string{
+		{"Central", "Ealing Broadway", "Westbound - Platform 1"},
+		{"Elizabeth", "Reading, via \"Slough\"", "Platform\tB"},
+	},
+}
+
+func TestTableSelect(t *testing.T) {
+	tests := []struct {
+		name      string
+		columns   []string
+		want      string
+		wantError bool
+	}{
+		{"single column", []string{"line"}, "line|Central|Elizabeth", false},
+		{"reordered", []string{"platform", "line"}, "platform,line|Westbound - Platform 1,Central|Platform\tB,Elizabeth", false},
+		{"unknown column", []string{"colour"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testTable.Select(tt.columns)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("Select(%v) expected error, got nil", tt.columns)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Select(%v) unexpected error: %v", tt.columns, err)
+			}
+
+			rows := []string{strings.Join(got.Columns, ",")}
+			for _, row := range got.Rows {
+				rows = append(rows, strings.Join(row, ","))
+			}
+			if joined := strings.Join(rows, "|"); joined != tt.want {
+				t.Errorf("Select(%v) = %q, want %q", tt.columns, joined, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var out strings.Builder
+	if err := WriteCSV(&out, testTable); err != nil {
+		t.Fatalf("WriteCSV() error: %v", err)
+	}
+
+	want := "line,destination,platform\n" +
+		"Central,Ealing Broadway,Westbound - Platform 1\n" +
+		"Elizabeth,\"Reading, via \"\"Slough\"\"\",Platform\tB\n"
+	if out.String() != want {
+		t.Errorf("WriteCSV() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestWriteTSV(t *testing.T) {
+	var out strings.Builder
+	if err := WriteTSV(&out, testTable); err != nil {
+		t.Fatalf("WriteTSV() error: %v", err)
+	}
+
+	want := "line\tdestination\tplatform\n" +
+		"Central\tEaling Broadway\tWestbound - Platform 1\n" +
+		"Elizabeth\tReading, via \"Slough\"\tPlatform B\n"
+	if out.String() != want {
+		t.Errorf("WriteTSV() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var out strings.Builder
+	table := Table{
+		Columns: []string{"line", "reason"},
+		Rows:    [][]string{{"Central", "Signal failure | delays\nexpected"}},
+	}
+	if err := WriteMarkdown(&out, table); err != nil {
+		t.Fatalf("WriteMarkdown() error: %v", err)
+	}
+
+	want := "| line | reason |\n" +
+		"| --- | --- |\n" +
+		"| Central | Signal failure \\| delays expected |\n"
+	if out.String() != want {
+		t.Errorf("WriteMarkdown() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var out strings.Builder
+	table := Table{
+		Columns: []string{"line", "destination"},
+		Rows:    [][]string{{"Circle", "Hammersmith <via Edgware Road>"}},
+		LineIDs: []string{"circle"},
+	}
+	if err := WriteHTML(&out, table); err != nil {
+		t.Fatalf("WriteHTML() error: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"<th>line</th><th>destination</th>",
+		`<span style="background:#ffd300;color:#000000;padding:0 0.4em;border-radius:3px">Circle</span>`,
+		"<td>Hammersmith &lt;via Edgware Road&gt;</td>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteHTML() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestVehicleArrivalsTable(t *testing.T) {
+	arrivals := []tfl.Arrival{
+		{LineID: "victoria", LineName: "Victoria", DestinationName: "Brixton", StationName: "Stockwell Underground Station", TimeToStation: 180},
+	}
+	got := VehicleArrivalsTable(arrivals, "231")
+	if len(got.Rows) != 1 || len(got.Rows[0]) != len(got.Columns) {
+		t.Fatalf("VehicleArrivalsTable() = %+v", got)
+	}
+	want := []string{"231", "Victoria", "victoria", "Brixton", "Stockwell", "3"}
+	for i, w := range want {
+		if got.Rows[0][i] != w {
+			t.Errorf("column %s = %q, want %q", got.Columns[i], got.Rows[0][i], w)
+		}
+	}
+	if got.LineIDs[0] != "victoria" {
+		t.Errorf("LineIDs = %v", got.LineIDs)
+	}
+}
@@ -0,0 +1,70 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs are the helpers available to --format template=...
+// alongside the standard text/template functions.
+var templateFuncs = template.FuncMap{
+	"lineColor": getLineColor,
+	"reset":     func() string { return reset },
+	"pad":       pad,
+	"padLeft":   padLeft,
+	"relTime":   relTime,
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"join":      strings.Join,
+}
+
+// ParseTemplate parses a user-supplied output template. Templates are
+// executed against the same structs as the JSON output, for example
+// DeparturesOutput or StatusOutput.
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("output").Funcs(templateFuncs).Parse(text)
+}
+
+func WriteTemplate(w io.Writer, tmpl *template.Template, data interface{}) error {
+	return tmpl.Execute(w, data)
+}
+
+func PrintTemplate(tmpl *template.Template, data interface{}) {
+	if err := WriteTemplate(os.Stdout, tmpl, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Error executing template: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// pad right-pads s with spaces to width columns.
+func pad(width int, s string) string {
+	return padWidth(s, width)
+}
+
+// padLeft left-pads s with spaces to width columns.
+func padLeft(width int, s string) string {
+	if n := width - displayWidth(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+// relTime describes a number of minutes from now the way the departures
+// board does, without colour: "Due", "1 min", "5 mins", "1h 20m".
+func relTime(mins int) string {
+	switch {
+	case mins <= 0:
+		return "Due"
+	case mins == 1:
+		return "1 min"
+	case mins < 60:
+		return fmt.Sprintf("%d mins", mins)
+	case mins%60 == 0:
+		return fmt.Sprintf("%dh", mins/60)
+	default:
+		return fmt.Sprintf("%dh %dm", mins/60, mins%60)
+	}
+}
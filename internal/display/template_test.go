@@ -0,0 +1,65 @@
+package display
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteTemplate(t *testing.T) {
+	data := DeparturesOutput{
+		Station: "Paddington",
+		Arrivals: []ArrivalJSON{
+			{Line: "Central", LineID: "central", Destination: "Ealing Broadway", MinutesAway: 0},
+			{Line: "Elizabeth", LineID: "elizabeth", Destination: "Reading", MinutesAway: 75},
+		},
+		Count: 2,
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"fields", `{{range .Arrivals}}{{.Line}} {{.MinutesAway}}{{"\n"}}{{end}}`, "Central 0\nElizabeth 75\n"},
+		{"pad", `{{range .Arrivals}}[{{pad 10 .Line}}]{{end}}`, "[Central   ][Elizabeth ]"},
+		{"padLeft", `{{range .Arrivals}}[{{padLeft 3 (printf "%d" .MinutesAway)}}]{{end}}`, "[  0][ 75]"},
+		{"relTime", `{{range .Arrivals}}{{relTime .MinutesAway}};{{end}}`, "Due;1h 15m;"},
+		{"lineColor", `{{with index .Arrivals 0}}{{lineColor .LineID}}{{.Line}}{{reset}}{{end}}`, "\033[48;2;220;36;31m\033[97mCentral" + reset},
+		{"station and count", `{{.Station}}: {{.Count}}`, "Paddington: 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseTemplate(%q) error: %v", tt.template, err)
+			}
+			var out strings.Builder
+			if err := WriteTemplate(&out, tmpl, data); err != nil {
+				t.Fatalf("WriteTemplate() error: %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("template %q = %q, want %q", tt.template, out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRelTime(t *testing.T) {
+	tests := []struct {
+		mins int
+		want string
+	}{
+		{0, "Due"},
+		{1, "1 min"},
+		{12, "12 mins"},
+		{60, "1h"},
+		{135, "2h 15m"},
+	}
+
+	for _, tt := range tests {
+		if got := relTime(tt.mins); got != tt.want {
+			t.Errorf("relTime(%d) = %q, want %q", tt.mins, got, tt.want)
+		}
+	}
+}
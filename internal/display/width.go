@@ -0,0 +1,160 @@
+package display
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// defaultWidth is used when stdout is a terminal whose size can't be read.
+const defaultWidth = 80
+
+// pipeWidth is used when stdout is not a terminal, so that piped output is
+// not truncated.
+const pipeWidth = 1 << 16
+
+// compactWidth is the terminal width below which text output switches to
+// the compact layout.
+const compactWidth = 60
+
+// terminalWidth returns the width of the terminal in columns: $COLUMNS if
+// set, otherwise the size of the terminal on stdout.
+var terminalWidth = func() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if n := ttyWidth(os.Stdout); n > 0 {
+		return n
+	}
+	if !IsTerminal(os.Stdout) {
+		return pipeWidth
+	}
+	return defaultWidth
+}
+
+func compact() bool {
+	return terminalWidth() < compactWidth
+}
+
+// piped reports whether output is going somewhere other than a terminal,
+// where columns are padded but never truncated.
+func piped() bool {
+	return terminalWidth() >= pipeWidth
+}
+
+// fitWidth returns the width available after indent columns, capped at max
+// for readability and never below a usable minimum.
+func fitWidth(max, indent int) int {
+	w := terminalWidth() - indent
+	if w > max {
+		return max
+	}
+	if w < 20 {
+		return 20
+	}
+	return w
+}
+
+// displayWidth returns the number of terminal columns s occupies. ANSI
+// escape sequences take no space, combining marks and other zero-width
+// characters take none, and East Asian wide characters and emoji take two.
+func displayWidth(s string) int {
+	width := 0
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case inEscape:
+			if r >= '@' && r <= '~' && r != '[' {
+				inEscape = false
+			}
+		case r == '\033':
+			inEscape = true
+		default:
+			width += runeWidth(r)
+		}
+	}
+	return width
+}
+
+// wideRanges are the code points that occupy two terminal columns.
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115F},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF},
+	{0x1F900, 0x1F9FF},
+	{0x20000, 0x2FFFD},
+	{0x30000, 0x3FFFD},
+}
+
+func runeWidth(r rune) int {
+	if r < 0x20 || (r >= 0x7F && r < 0xA0) {
+		return 0
+	}
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	for _, rg := range wideRanges {
+		if r >= rg.lo && r <= rg.hi {
+			return 2
+		}
+	}
+	return 1
+}
+
+// truncateWidth shortens s to at most width columns, marking the cut with
+// "..". A width below zero is treated as zero.
+func truncateWidth(s string, width int) string {
+	if width < 0 {
+		width = 0
+	}
+	if displayWidth(s) <= width {
+		return s
+	}
+	if width <= 2 {
+		return strings.Repeat(".", width)
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := runeWidth(r)
+		if used+w > width-2 {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	return b.String() + ".."
+}
+
+// padWidth right-pads s with spaces to width columns.
+func padWidth(s string, width int) string {
+	if n := width - displayWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// fitCell truncates and pads s to exactly width columns.
+func fitCell(s string, width int) string {
+	return padWidth(truncateWidth(s, width), width)
+}
+
+// fitColumn pads s to width columns, truncating it to fit only when output
+// is going to a terminal.
+func fitColumn(s string, width int) string {
+	if piped() {
+		return padWidth(s, width)
+	}
+	return fitCell(s, width)
+}
@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package display
+
+import "os"
+
+// ttyWidth is not supported on this platform; $COLUMNS or the default
+// width is used instead.
+func ttyWidth(f *os.File) int {
+	return 0
+}
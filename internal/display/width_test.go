@@ -0,0 +1,176 @@
+package display
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"tfl/internal/tfl"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"Paddington", 10},
+		{"Brent Cross – Hendon", 20},
+		{"Café", 4},
+		{"Café", 4},
+		{"東京", 4},
+		{"🚇 Tube", 7},
+		{"\033[1mDue\033[0m", 3},
+		{"\033[48;2;220;36;31m\033[97m Central \033[0m", 9},
+	}
+
+	for _, tt := range tests {
+		if got := displayWidth(tt.s); got != tt.want {
+			t.Errorf("displayWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"Ealing Broadway", 20, "Ealing Broadway"},
+		{"Heathrow Terminal 5", 10, "Heathrow.."},
+		{"東京駅行き", 7, "東京.."},
+		{"Café Royal", 6, "Café.."},
+		{"Epping", 2, ".."},
+		{"Epping", 0, ""},
+	}
+
+	for _, tt := range tests {
+		got := truncateWidth(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("truncateWidth(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if displayWidth(got) > tt.width {
+			t.Errorf("truncateWidth(%q, %d) is %d columns wide", tt.s, tt.width, displayWidth(got))
+		}
+	}
+}
+
+func TestFitCell(t *testing.T) {
+	if got := fitCell("Café", 6); got != "Café  " {
+		t.Errorf("fitCell() = %q", got)
+	}
+	if got := fitCell("Hammersmith & City", 14); got != "Hammersmith .." {
+		t.Errorf("fitCell() = %q", got)
+	}
+}
+
+func TestNewArrivalLayout(t *testing.T) {
+	tests := []struct {
+		width         int
+		compact       bool
+		destWidth     int
+		platformWidth int
+		piped         bool
+	}{
+		{pipeWidth, false, 28, pipeWidth, true},
+		{120, false, 28, 55, false},
+		{80, false, 28, 15, false},
+		{64, false, 15, 12, false},
+		{59, true, 40, 0, false},
+		{30, true, 11, 0, false},
+		{20, true, 8, 0, false},
+	}
+
+	for _, tt := range tests {
+		got := newArrivalLayout(tt.width)
+		want := arrivalLayout{compact: tt.compact, destWidth: tt.destWidth, platformWidth: tt.platformWidth, piped: tt.piped}
+		if got != want {
+			t.Errorf("newArrivalLayout(%d) = %+v, want %+v", tt.width, got, want)
+		}
+	}
+}
+
+func TestWrapTextWidth(t *testing.T) {
+	lines := wrapText("東京 東京 東京", 9)
+	if len(lines) != 2 || lines[0] != "東京 東京" {
+		t.Errorf("wrapText() = %q", lines)
+	}
+}
+
+func TestNarrowTerminal(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	stdout, width := os.Stdout, terminalWidth
+	defer func() { os.Stdout, terminalWidth = stdout, width }()
+	os.Stdout = devNull
+
+	if got := truncateWidth("Epping", -7); got != "" {
+		t.Errorf("truncateWidth() with negative width = %q", got)
+	}
+
+	arrivals := []tfl.Arrival{{
+		LineID: "central", LineName: "Central", DestinationName: "Epping",
+		StationName: "Bethnal Green Underground Station", PlatformName: "Eastbound - Platform 1",
+		VehicleID: "123", TimeToStation: 120, ExpectedArrival: time.Now().Add(2 * time.Minute),
+	}}
+	stops := []tfl.StopPoint{{ID: "940GZZLUBLG", Name: "Bethnal Green Underground Station"}}
+
+	for _, cols := range []int{1, 5, 12, 19} {
+		terminalWidth = func() int { return cols }
+		PrintVehicleArrivals(arrivals, "123")
+		PrintStopPoints(stops)
+		PrintArrivals(arrivals, "Bethnal Green", true)
+	}
+}
+
+// captureStdout returns what f writes to stdout.
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	defer func() { os.Stdout = stdout }()
+	f()
+	w.Close()
+	return <-done
+}
+
+func TestPipedOutputNotTruncated(t *testing.T) {
+	width := terminalWidth
+	defer func() { terminalWidth = width }()
+	terminalWidth = func() int { return pipeWidth }
+
+	name := "Heathrow Terminals 2 & 3 Underground Station"
+	if len(name) != 44 {
+		t.Fatalf("test name is %d columns", len(name))
+	}
+
+	out := captureStdout(t, func() {
+		PrintStopPoints([]tfl.StopPoint{{ID: "940GZZLUHRC", Name: name}})
+		PrintArrivals([]tfl.Arrival{{
+			LineID: "piccadilly", LineName: "Piccadilly", DestinationName: name,
+			PlatformName: "Westbound - Platform 1", TimeToStation: 120,
+			ExpectedArrival: time.Now().Add(2 * time.Minute),
+		}}, "Acton Town", false)
+	})
+	if strings.Count(out, name) != 2 {
+		t.Errorf("piped output truncated:\n%s", out)
+	}
+	if strings.Contains(out, "..") {
+		t.Errorf("piped output has a truncation marker:\n%s", out)
+	}
+}
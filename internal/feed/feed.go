@@ -0,0 +1,147 @@
+// Package feed renders disruptions and line status changes, as recorded by
+// the notify state, as an Atom feed.
+package feed
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"tfl/internal/notify"
+)
+
+// goodService is the status severity TfL uses for a normal service.
+const goodService = 10
+
+type Link struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type Person struct {
+	Name string `xml:"name"`
+}
+
+type Text struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+type Category struct {
+	Term string `xml:"term,attr"`
+}
+
+type Entry struct {
+	ID       string   `xml:"id"`
+	Title    string   `xml:"title"`
+	Updated  string   `xml:"updated"`
+	Category Category `xml:"category"`
+	Content  Text     `xml:"content"`
+}
+
+type Feed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Author  Person   `xml:"author"`
+	Links   []Link   `xml:"link,omitempty"`
+	Entries []Entry  `xml:"entry"`
+}
+
+// Build creates a feed from the state. Every active disruption is an entry,
+// as is every line that is not in good service or changed status within
+// maxAge. Entry IDs are derived from the content, so an entry keeps its ID
+// across runs and a new ID means something genuinely changed.
+func Build(state *notify.State, maxAge time.Duration, now time.Time) Feed {
+	f := Feed{
+		ID:     "urn:tfl-cli:feed",
+		Title:  "TfL disruptions and line status",
+		Author: Person{Name: "tfl-cli"},
+	}
+	var entries []Entry
+	var times []time.Time
+
+	for key, d := range state.Disruptions {
+		entries = append(entries, Entry{
+			ID:       "urn:tfl-cli:disruption:" + key,
+			Title:    d.Category,
+			Category: Category{Term: "disruption"},
+			Content:  Text{Type: "text", Body: d.Description},
+		})
+		times = append(times, d.Since)
+	}
+
+	for lineID, l := range state.Lines {
+		recent := l.PreviousStatus != "" && now.Sub(l.Since) <= maxAge
+		if l.Severity == goodService && !recent {
+			continue
+		}
+
+		sum := sha1.Sum([]byte(fmt.Sprintf("%s|%d|%s", lineID, l.Severity, l.Reason)))
+		body := l.Reason
+		if body == "" {
+			body = l.Status
+		}
+		if l.PreviousStatus != "" {
+			body = fmt.Sprintf("Was %s. %s", l.PreviousStatus, body)
+		}
+
+		entries = append(entries, Entry{
+			ID:       "urn:tfl-cli:status:" + lineID + ":" + hex.EncodeToString(sum[:6]),
+			Title:    fmt.Sprintf("%s: %s", l.Line, l.Status),
+			Category: Category{Term: "line-status"},
+			Content:  Text{Type: "text", Body: body},
+		})
+		times = append(times, l.Since)
+	}
+
+	latest := time.Time{}
+	for i := range entries {
+		entries[i].Updated = times[i].UTC().Format(time.RFC3339)
+		if times[i].After(latest) {
+			latest = times[i]
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Updated != entries[j].Updated {
+			return entries[i].Updated > entries[j].Updated
+		}
+		return entries[i].ID < entries[j].ID
+	})
+
+	f.Entries = entries
+	if latest.IsZero() {
+		latest = now
+	}
+	f.Updated = latest.UTC().Format(time.RFC3339)
+	return f
+}
+
+// WithSelf returns a copy of the feed whose self link is selfURL, leaving f
+// unchanged so a cached feed can be served under different URLs.
+func (f Feed) WithSelf(selfURL string) Feed {
+	f.Links = nil
+	if selfURL != "" {
+		f.Links = []Link{{Href: selfURL, Rel: "self"}}
+	}
+	return f
+}
+
+func (f Feed) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,91 @@
+package feed
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"tfl/internal/notify"
+)
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	state := &notify.State{
+		Lines: map[string]notify.LineState{
+			"central":  {Line: "Central", Status: "Minor Delays", Severity: 9, Reason: "Signal failure", PreviousStatus: "Good Service", Since: now.Add(-time.Hour)},
+			"victoria": {Line: "Victoria", Status: "Good Service", Severity: 10, Since: now.Add(-48 * time.Hour)},
+			"jubilee":  {Line: "Jubilee", Status: "Good Service", Severity: 10, PreviousStatus: "Part Suspended", Since: now.Add(-2 * time.Hour)},
+			"district": {Line: "District", Status: "Good Service", Severity: 10, PreviousStatus: "Minor Delays", Since: now.Add(-30 * 24 * time.Hour)},
+		},
+		Disruptions: map[string]notify.DisruptionState{
+			"abc123": {Category: "RealTime", Description: "Signal failure at Bank", Since: now.Add(-30 * time.Minute)},
+		},
+	}
+
+	f := Build(state, 24*time.Hour, now)
+
+	var ids []string
+	for _, e := range f.Entries {
+		ids = append(ids, e.ID)
+	}
+	if len(f.Entries) != 3 {
+		t.Fatalf("Build() = %d entries %v, want disruption, central and recently restored jubilee", len(f.Entries), ids)
+	}
+	if f.Entries[0].ID != "urn:tfl-cli:disruption:abc123" {
+		t.Errorf("newest entry = %s, want the disruption", f.Entries[0].ID)
+	}
+	if f.Updated != "2026-10-18T11:30:00Z" {
+		t.Errorf("feed updated = %s, want newest entry time", f.Updated)
+	}
+
+	again := Build(state, 24*time.Hour, now.Add(time.Minute))
+	for i := range f.Entries {
+		if f.Entries[i].ID != again.Entries[i].ID {
+			t.Errorf("entry IDs not stable: %s != %s", f.Entries[i].ID, again.Entries[i].ID)
+		}
+	}
+
+	changed := *state
+	changed.Lines = map[string]notify.LineState{
+		"central": {Line: "Central", Status: "Severe Delays", Severity: 6, Reason: "Signal failure", Since: now},
+	}
+	changed.Disruptions = nil
+	if Build(&changed, time.Hour, now).Entries[0].ID == f.Entries[1].ID {
+		t.Error("changed status kept the same entry ID")
+	}
+}
+
+func TestFeedWrite(t *testing.T) {
+	f := Feed{ID: "urn:tfl-cli:feed", Title: "TfL", Updated: "2026-10-18T12:00:00Z",
+		Entries: []Entry{{ID: "urn:x", Title: "Central & co", Updated: "2026-10-18T12:00:00Z", Content: Text{Type: "text", Body: "<delays>"}}}}
+
+	var out strings.Builder
+	if err := f.Write(&out); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<feed xmlns="http://www.w3.org/2005/Atom">`,
+		`<title>Central &amp; co</title>`,
+		`<content type="text">&lt;delays&gt;</content>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("feed missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestWithSelf(t *testing.T) {
+	f := Feed{ID: "urn:tfl-cli:feed"}
+	linked := f.WithSelf("https://example.com/tfl.atom")
+	if len(linked.Links) != 1 || linked.Links[0].Href != "https://example.com/tfl.atom" || linked.Links[0].Rel != "self" {
+		t.Errorf("WithSelf() links = %+v", linked.Links)
+	}
+	if len(f.Links) != 0 {
+		t.Errorf("WithSelf() modified the original feed: %+v", f.Links)
+	}
+	if len(linked.WithSelf("").Links) != 0 {
+		t.Error("WithSelf(\"\") kept a self link")
+	}
+}
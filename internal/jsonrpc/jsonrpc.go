@@ -0,0 +1,135 @@
+// Package jsonrpc serves JSON-RPC 2.0 over a byte stream such as stdio.
+// Requests may be separated by newlines or simply concatenated, and batch
+// requests are supported.
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	// CodeServerError is used for failures talking to the TfL API.
+	CodeServerError = -32000
+)
+
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Handler answers a single method call. Returning an *Error sets the error
+// code; any other error is reported as CodeServerError.
+type Handler func(method string, params json.RawMessage) (interface{}, error)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// A response carries either a result or an error, never both. They are
+// separate types so that "result" is present on success even when it is
+// null, as JSON-RPC 2.0 requires.
+type response interface{}
+
+type resultResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result"`
+}
+
+type errorResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Error   *Error          `json:"error"`
+}
+
+var nullID = json.RawMessage("null")
+
+// Serve reads requests from r until EOF and writes one response per line
+// to w. Notifications (requests without an id) get no response.
+func Serve(r io.Reader, w io.Writer, handler Handler) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	out := bufio.NewWriter(w)
+	enc := json.NewEncoder(out)
+
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return out.Flush()
+			}
+			// The stream cannot be resynchronised after malformed JSON.
+			_ = enc.Encode(errorResponse{JSONRPC: "2.0", ID: nullID, Error: &Error{Code: CodeParseError, Message: err.Error()}})
+			return out.Flush()
+		}
+
+		if reply := handle(raw, handler); reply != nil {
+			if err := enc.Encode(reply); err != nil {
+				return err
+			}
+		}
+		if err := out.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+func handle(raw json.RawMessage, handler Handler) response {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		return call(raw, handler)
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(raw, &batch); err != nil || len(batch) == 0 {
+		return errorResponse{JSONRPC: "2.0", ID: nullID, Error: &Error{Code: CodeInvalidRequest, Message: "invalid batch"}}
+	}
+
+	var replies []response
+	for _, item := range batch {
+		if resp := call(item, handler); resp != nil {
+			replies = append(replies, resp)
+		}
+	}
+	if len(replies) == 0 {
+		return nil
+	}
+	return replies
+}
+
+// call answers a single request, returning nil for a notification.
+func call(raw json.RawMessage, handler Handler) response {
+	var req request
+	if err := json.Unmarshal(raw, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse{JSONRPC: "2.0", ID: nullID, Error: &Error{Code: CodeInvalidRequest, Message: "invalid request"}}
+	}
+
+	result, err := handler(req.Method, req.Params)
+	if req.ID == nil {
+		return nil
+	}
+
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{Code: CodeServerError, Message: err.Error()}
+		}
+		return errorResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	return resultResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
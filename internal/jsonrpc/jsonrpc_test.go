@@ -0,0 +1,75 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func echoHandler(method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case "echo":
+		var p struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+		return p.Text, nil
+	case "ping":
+		return nil, nil
+	case "fail":
+		return nil, errors.New("upstream unavailable")
+	default:
+		return nil, &Error{Code: CodeMethodNotFound, Message: "method not found"}
+	}
+}
+
+func TestServe(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"result", `{"jsonrpc":"2.0","id":1,"method":"echo","params":{"text":"hi"}}`,
+			`{"jsonrpc":"2.0","id":1,"result":"hi"}`},
+		{"string id", `{"jsonrpc":"2.0","id":"a","method":"echo","params":{"text":"hi"}}`,
+			`{"jsonrpc":"2.0","id":"a","result":"hi"}`},
+		{"null result", `{"jsonrpc":"2.0","id":5,"method":"ping"}`,
+			`{"jsonrpc":"2.0","id":5,"result":null}`},
+		{"notification has no reply", `{"jsonrpc":"2.0","method":"echo","params":{"text":"hi"}}`, ``},
+		{"unknown method", `{"jsonrpc":"2.0","id":2,"method":"nope"}`,
+			`{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"method not found"}}`},
+		{"handler error", `{"jsonrpc":"2.0","id":3,"method":"fail"}`,
+			`{"jsonrpc":"2.0","id":3,"error":{"code":-32000,"message":"upstream unavailable"}}`},
+		{"missing version", `{"id":4,"method":"echo"}`,
+			`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"invalid request"}}`},
+		{"batch", `[{"jsonrpc":"2.0","id":1,"method":"echo","params":{"text":"a"}},{"jsonrpc":"2.0","method":"echo","params":{"text":"b"}}]`,
+			`[{"jsonrpc":"2.0","id":1,"result":"a"}]`},
+		{"empty batch", `[]`,
+			`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"invalid batch"}}`},
+		{"several requests", "{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"echo\",\"params\":{\"text\":\"a\"}}\n{\"jsonrpc\":\"2.0\",\"id\":2,\"method\":\"echo\",\"params\":{\"text\":\"b\"}}",
+			"{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":\"a\"}\n{\"jsonrpc\":\"2.0\",\"id\":2,\"result\":\"b\"}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			if err := Serve(strings.NewReader(tt.input), &out, echoHandler); err != nil {
+				t.Fatalf("Serve() error: %v", err)
+			}
+			if got := strings.TrimSpace(out.String()); got != tt.want {
+				t.Errorf("Serve() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeParseError(t *testing.T) {
+	var out strings.Builder
+	_ = Serve(strings.NewReader(`{"jsonrpc":`), &out, echoHandler)
+	if !strings.Contains(out.String(), `"code":-32700`) {
+		t.Errorf("Serve() = %s, want parse error", out.String())
+	}
+}
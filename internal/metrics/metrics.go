@@ -0,0 +1,201 @@
+// Package metrics is a minimal Prometheus registry that renders the text
+// exposition format, enough for the exporter command without pulling in
+// the full client library.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type Labels map[string]string
+
+const (
+	typeGauge     = "gauge"
+	typeCounter   = "counter"
+	typeHistogram = "histogram"
+)
+
+// DefaultBuckets suit HTTP request latencies in seconds.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type series struct {
+	labels  Labels
+	value   float64
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+type family struct {
+	name    string
+	help    string
+	typ     string
+	buckets []float64
+	series  map[string]*series
+}
+
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+func (r *Registry) family(name, help, typ string) *family {
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{name: name, help: help, typ: typ, series: make(map[string]*series)}
+		if typ == typeHistogram {
+			f.buckets = DefaultBuckets
+		}
+		r.families[name] = f
+	}
+	return f
+}
+
+func (f *family) get(labels Labels) *series {
+	key := formatLabels(labels)
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: labels}
+		if f.typ == typeHistogram {
+			s.buckets = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (r *Registry) SetGauge(name, help string, labels Labels, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.family(name, help, typeGauge).get(labels).value = value
+}
+
+// Sample is one labelled value of a gauge.
+type Sample struct {
+	Labels Labels
+	Value  float64
+}
+
+// ReplaceGauge swaps every series of a gauge for samples in one step, so
+// series that disappear from the source (a cleared disruption category,
+// say) stop being exported and a scrape never sees a partial set.
+func (r *Registry) ReplaceGauge(name, help string, samples []Sample) {
+	fresh := make(map[string]*series, len(samples))
+	for _, s := range samples {
+		fresh[formatLabels(s.Labels)] = &series{labels: s.Labels, value: s.Value}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.family(name, help, typeGauge).series = fresh
+}
+
+func (r *Registry) IncCounter(name, help string, labels Labels) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.family(name, help, typeCounter).get(labels).value++
+}
+
+func (r *Registry) Observe(name, help string, labels Labels, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := r.family(name, help, typeHistogram)
+	s := f.get(labels)
+	for i, upper := range f.buckets {
+		if value <= upper {
+			s.buckets[i]++
+		}
+	}
+	s.sum += value
+	s.count++
+}
+
+// WriteText renders every metric in the Prometheus text format, sorted by
+// name and labels so output is stable between scrapes.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		f := r.families[name]
+		fmt.Fprintf(&b, "# HELP %s %s\n", name, f.help)
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, f.typ)
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := f.series[key]
+			if f.typ != typeHistogram {
+				fmt.Fprintf(&b, "%s%s %s\n", name, key, formatValue(s.value))
+				continue
+			}
+			for i, upper := range f.buckets {
+				fmt.Fprintf(&b, "%s_bucket%s %d\n", name, formatLabels(withLabel(s.labels, "le", formatValue(upper))), s.buckets[i])
+			}
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", name, formatLabels(withLabel(s.labels, "le", "+Inf")), s.count)
+			fmt.Fprintf(&b, "%s_sum%s %s\n", name, key, formatValue(s.sum))
+			fmt.Fprintf(&b, "%s_count%s %d\n", name, key, s.count)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func withLabel(labels Labels, name, value string) Labels {
+	out := make(Labels, len(labels)+1)
+	for k, v := range labels {
+		out[k] = v
+	}
+	out[name] = value
+	return out
+}
+
+func formatLabels(labels Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+`="`+labelEscaper.Replace(labels[name])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// labelEscaper escapes label values as the text format requires: only
+// backslash, double quote and newline, unlike Go's %q.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return fmt.Sprintf("%g", v)
+}
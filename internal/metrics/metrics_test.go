@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistryWriteText(t *testing.T) {
+	r := NewRegistry()
+	r.SetGauge("tfl_line_status_severity", "Line status severity.", Labels{"line_id": "central", "line": "Central"}, 10)
+	r.IncCounter("tfl_api_errors_total", "API errors.", Labels{"endpoint": "status"})
+	r.IncCounter("tfl_api_errors_total", "API errors.", Labels{"endpoint": "status"})
+	r.Observe("tfl_api_request_duration_seconds", "Request latency.", Labels{"endpoint": "status"}, 0.3)
+
+	var out strings.Builder
+	if err := r.WriteText(&out); err != nil {
+		t.Fatalf("WriteText() error: %v", err)
+	}
+	got := out.String()
+
+	for _, want := range []string{
+		"# TYPE tfl_line_status_severity gauge\n",
+		`tfl_line_status_severity{line="Central",line_id="central"} 10` + "\n",
+		`tfl_api_errors_total{endpoint="status"} 2` + "\n",
+		"# TYPE tfl_api_request_duration_seconds histogram\n",
+		`tfl_api_request_duration_seconds_bucket{endpoint="status",le="0.25"} 0` + "\n",
+		`tfl_api_request_duration_seconds_bucket{endpoint="status",le="0.5"} 1` + "\n",
+		`tfl_api_request_duration_seconds_bucket{endpoint="status",le="+Inf"} 1` + "\n",
+		`tfl_api_request_duration_seconds_count{endpoint="status"} 1` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestReplaceGauge(t *testing.T) {
+	r := NewRegistry()
+	r.SetGauge("tfl_disruptions", "Disruptions.", Labels{"category": "RealTime"}, 2)
+	r.ReplaceGauge("tfl_disruptions", "Disruptions.", []Sample{
+		{Labels{"category": "PlannedWork"}, 3},
+		{Labels{"category": "Information"}, 1},
+	})
+
+	var out strings.Builder
+	_ = r.WriteText(&out)
+	got := out.String()
+	if strings.Contains(got, "RealTime") {
+		t.Errorf("replaced series still exported:\n%s", got)
+	}
+	for _, want := range []string{
+		`tfl_disruptions{category="Information"} 1` + "\n",
+		`tfl_disruptions{category="PlannedWork"} 3` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestLabelEscaping(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{`Harrow & Wealdstone`, `{station="Harrow & Wealdstone"}`},
+		{`King's "Cross"`, `{station="King's \"Cross\""}`},
+		{`back\slash`, `{station="back\\slash"}`},
+		{"two\nlines", `{station="two\nlines"}`},
+		{"tab\there", "{station=\"tab\there\"}"},
+		{"Brühl", `{station="Brühl"}`},
+	}
+
+	for _, tt := range tests {
+		if got := formatLabels(Labels{"station": tt.value}); got != tt.want {
+			t.Errorf("formatLabels(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+type Sink interface {
+	Notify(change Change) error
+}
+
+func Title(change Change) string {
+	if change.Kind == KindDisruption {
+		if change.Cleared {
+			return "Cleared: " + change.Disruption.Category
+		}
+		return "Disruption: " + change.Disruption.Category
+	}
+	return fmt.Sprintf("%s: %s", change.New.Line, change.New.Status)
+}
+
+func Message(change Change) string {
+	if change.Kind == KindDisruption {
+		return change.Disruption.Description
+	}
+	msg := fmt.Sprintf("Was %s", change.Old.Status)
+	if change.New.Reason != "" {
+		msg += ". " + change.New.Reason
+	}
+	return msg
+}
+
+// CommandSink runs a shell command for each change, passing the details in
+// TFL_* environment variables. Line fields are empty for disruptions.
+type CommandSink struct {
+	Command string
+}
+
+func (s CommandSink) Notify(change Change) error {
+	cmd := exec.Command("sh", "-c", s.Command)
+	cmd.Env = append(os.Environ(),
+		"TFL_KIND="+change.Kind,
+		"TFL_LINE="+change.New.Line,
+		"TFL_LINE_ID="+change.LineID,
+		"TFL_OLD_STATUS="+change.Old.Status,
+		"TFL_NEW_STATUS="+change.New.Status,
+		"TFL_OLD_SEVERITY="+strconv.Itoa(change.Old.Severity),
+		"TFL_NEW_SEVERITY="+strconv.Itoa(change.New.Severity),
+		"TFL_REASON="+change.New.Reason,
+		"TFL_TITLE="+Title(change),
+		"TFL_MESSAGE="+Message(change),
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// DesktopSink invokes a notifier such as notify-send with the title and
+// message as its final two arguments.
+type DesktopSink struct {
+	Program string
+	Args    []string
+}
+
+func (s DesktopSink) Notify(change Change) error {
+	args := append(append([]string{}, s.Args...), Title(change), Message(change))
+	return exec.Command(s.Program, args...).Run()
+}
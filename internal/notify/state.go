@@ -0,0 +1,233 @@
+package notify
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"tfl/internal/tfl"
+)
+
+// LineState summarises every status TfL reports for a line, which can be
+// several at once (a part closure and minor delays elsewhere, say). Status
+// and Reason join them; Severity is the first, which TfL lists as the most
+// significant. Key identifies the whole set for change detection.
+type LineState struct {
+	Line           string    `json:"line"`
+	Status         string    `json:"status"`
+	Severity       int       `json:"severity"`
+	Reason         string    `json:"reason,omitempty"`
+	Key            string    `json:"key,omitempty"`
+	PreviousStatus string    `json:"previous_status,omitempty"`
+	Since          time.Time `json:"since"`
+}
+
+type DisruptionState struct {
+	Category    string    `json:"category"`
+	Description string    `json:"description"`
+	Since       time.Time `json:"since"`
+}
+
+// State is the last known status of each watched line, keyed by line ID,
+// and the active disruptions, keyed by DisruptionKey. Disruptions is nil
+// until disruptions have been polled at least once.
+type State struct {
+	Lines       map[string]LineState       `json:"lines"`
+	Disruptions map[string]DisruptionState `json:"disruptions,omitempty"`
+	UpdatedAt   time.Time                  `json:"updated_at"`
+}
+
+const (
+	KindLineStatus = "line_status"
+	KindDisruption = "disruption"
+)
+
+// Change is either a line status change (Old and New set) or a disruption
+// that started or cleared (Disruption and Cleared set), as told by Kind.
+type Change struct {
+	Kind       string
+	LineID     string
+	Old        LineState
+	New        LineState
+	Disruption DisruptionState
+	Cleared    bool
+}
+
+// DisruptionKey identifies a disruption by its content, since the API does
+// not give disruptions stable IDs.
+func DisruptionKey(category, description string) string {
+	sum := sha1.Sum([]byte(category + "\n" + description))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// statusKey identifies the set of statuses of a line regardless of the
+// order TfL lists them in, so any change to any status can be detected.
+func statusKey(statuses []tfl.Status) string {
+	parts := make([]string, 0, len(statuses))
+	for _, st := range statuses {
+		parts = append(parts, strconv.Itoa(st.StatusSeverity)+"|"+st.Reason)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "\n")
+}
+
+func newLineState(line tfl.LineStatus, now time.Time) LineState {
+	var descriptions, reasons []string
+	seen := make(map[string]bool)
+	for _, st := range line.LineStatuses {
+		if !seen["d"+st.StatusSeverityDescription] {
+			seen["d"+st.StatusSeverityDescription] = true
+			descriptions = append(descriptions, st.StatusSeverityDescription)
+		}
+		if st.Reason != "" && !seen["r"+st.Reason] {
+			seen["r"+st.Reason] = true
+			reasons = append(reasons, st.Reason)
+		}
+	}
+	return LineState{
+		Line:     line.Name,
+		Status:   strings.Join(descriptions, ", "),
+		Severity: line.LineStatuses[0].StatusSeverity,
+		Reason:   strings.Join(reasons, "\n"),
+		Key:      statusKey(line.LineStatuses),
+		Since:    now,
+	}
+}
+
+// unchanged reports whether current describes the same statuses as l. State
+// saved before keys were recorded falls back to severity and reason.
+func (l LineState) unchanged(current LineState) bool {
+	if l.Key == "" {
+		return l.Severity == current.Severity && l.Reason == current.Reason
+	}
+	return l.Key == current.Key
+}
+
+// DefaultStatePath returns where a poller called name keeps its state.
+// Each poller needs its own file, or one would consume the other's changes.
+func DefaultStatePath(name string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tfl", name+"-state.json"), nil
+}
+
+// LoadState reads the state file at path. A missing file yields an empty
+// state so the first poll records a baseline without notifying.
+func LoadState(path string) (*State, error) {
+	state := &State{Lines: make(map[string]LineState)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Lines == nil {
+		state.Lines = make(map[string]LineState)
+	}
+	return state, nil
+}
+
+// Save writes the state atomically so an interrupted daemon never leaves
+// a truncated file behind.
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Update records the latest statuses and returns the lines where any
+// status's severity or reason differs from the previous poll. Lines seen
+// for the first time are recorded but not reported. An empty lineIDs
+// matches every line.
+func (s *State) Update(statuses []tfl.LineStatus, lineIDs []string, now time.Time) []Change {
+	watched := make(map[string]bool)
+	for _, id := range lineIDs {
+		watched[id] = true
+	}
+
+	var changes []Change
+	for _, line := range statuses {
+		if len(watched) > 0 && !watched[line.ID] {
+			continue
+		}
+		if len(line.LineStatuses) == 0 {
+			continue
+		}
+
+		current := newLineState(line, now)
+		previous, known := s.Lines[line.ID]
+		if known && previous.unchanged(current) {
+			continue
+		}
+
+		if known {
+			current.PreviousStatus = previous.Status
+		}
+		s.Lines[line.ID] = current
+		if known {
+			changes = append(changes, Change{Kind: KindLineStatus, LineID: line.ID, Old: previous, New: current})
+		}
+	}
+
+	s.UpdatedAt = now
+	return changes
+}
+
+// UpdateDisruptions records the active disruptions and returns those that
+// started or cleared since the previous poll. The first poll only records
+// a baseline.
+func (s *State) UpdateDisruptions(disruptions []tfl.Disruption, now time.Time) []Change {
+	baseline := s.Disruptions == nil
+	previous := s.Disruptions
+	s.Disruptions = make(map[string]DisruptionState)
+
+	var changes []Change
+	for _, d := range disruptions {
+		key := DisruptionKey(d.CategoryDescription, d.Description)
+		if existing, ok := previous[key]; ok {
+			s.Disruptions[key] = existing
+			continue
+		}
+
+		current := DisruptionState{Category: d.CategoryDescription, Description: d.Description, Since: now}
+		s.Disruptions[key] = current
+		if !baseline {
+			changes = append(changes, Change{Kind: KindDisruption, Disruption: current})
+		}
+	}
+
+	for key, old := range previous {
+		if _, ok := s.Disruptions[key]; !ok {
+			changes = append(changes, Change{Kind: KindDisruption, Disruption: old, Cleared: true})
+		}
+	}
+
+	s.UpdatedAt = now
+	return changes
+}
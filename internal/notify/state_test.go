@@ -0,0 +1,145 @@
+package notify
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tfl/internal/tfl"
+)
+
+// lineStatus builds a status from API-shaped JSON, as the client would.
+func lineStatus(id, name string, severity int, description, reason string) tfl.LineStatus {
+	raw, _ := json.Marshal(map[string]interface{}{
+		"id":   id,
+		"name": name,
+		"lineStatuses": []map[string]interface{}{{
+			"statusSeverity":            severity,
+			"statusSeverityDescription": description,
+			"reason":                    reason,
+		}},
+	})
+	var line tfl.LineStatus
+	_ = json.Unmarshal(raw, &line)
+	return line
+}
+
+// multiStatus builds a line reporting several statuses at once.
+func multiStatus(id, name string, statuses ...tfl.LineStatus) tfl.LineStatus {
+	line := tfl.LineStatus{ID: id, Name: name}
+	for _, st := range statuses {
+		line.LineStatuses = append(line.LineStatuses, st.LineStatuses...)
+	}
+	return line
+}
+
+func TestStateUpdate(t *testing.T) {
+	now := time.Now()
+	state := &State{Lines: make(map[string]LineState)}
+
+	initial := []tfl.LineStatus{
+		lineStatus("central", "Central", 10, "Good Service", ""),
+		lineStatus("victoria", "Victoria", 10, "Good Service", ""),
+	}
+	if changes := state.Update(initial, nil, now); len(changes) != 0 {
+		t.Fatalf("first update reported %d changes, want 0", len(changes))
+	}
+
+	tests := []struct {
+		name     string
+		statuses []tfl.LineStatus
+		lineIDs  []string
+		want     int
+	}{
+		{"unchanged", initial, nil, 0},
+		{"severity change", []tfl.LineStatus{
+			lineStatus("central", "Central", 9, "Minor Delays", "Signal failure"),
+		}, nil, 1},
+		{"reason change only", []tfl.LineStatus{
+			lineStatus("central", "Central", 9, "Minor Delays", "Earlier signal failure"),
+		}, nil, 1},
+		{"unwatched line ignored", []tfl.LineStatus{
+			lineStatus("victoria", "Victoria", 6, "Severe Delays", "Train cancellations"),
+		}, []string{"central"}, 0},
+		{"watched line reported", []tfl.LineStatus{
+			lineStatus("victoria", "Victoria", 5, "Part Closure", ""),
+		}, []string{"victoria"}, 1},
+		{"new line not reported", []tfl.LineStatus{
+			lineStatus("jubilee", "Jubilee", 10, "Good Service", ""),
+		}, nil, 0},
+		{"second status added", []tfl.LineStatus{
+			multiStatus("jubilee", "Jubilee",
+				lineStatus("", "", 5, "Part Closure", "No service Stanmore - Wembley Park"),
+				lineStatus("", "", 9, "Minor Delays", "Signal failure")),
+		}, nil, 1},
+		{"same statuses reordered", []tfl.LineStatus{
+			multiStatus("jubilee", "Jubilee",
+				lineStatus("", "", 9, "Minor Delays", "Signal failure"),
+				lineStatus("", "", 5, "Part Closure", "No service Stanmore - Wembley Park")),
+		}, nil, 0},
+		{"second status changed", []tfl.LineStatus{
+			multiStatus("jubilee", "Jubilee",
+				lineStatus("", "", 5, "Part Closure", "No service Stanmore - Wembley Park"),
+				lineStatus("", "", 6, "Severe Delays", "Signal failure")),
+		}, nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := state.Update(tt.statuses, tt.lineIDs, now)
+			if len(changes) != tt.want {
+				t.Errorf("Update() = %d changes, want %d", len(changes), tt.want)
+			}
+		})
+	}
+}
+
+func TestStateSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() on missing file: %v", err)
+	}
+	state.Update([]tfl.LineStatus{lineStatus("central", "Central", 9, "Minor Delays", "Signal failure")}, nil, time.Now())
+
+	if err := state.Save(path); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error: %v", err)
+	}
+	got := loaded.Lines["central"]
+	if got.Severity != 9 || got.Reason != "Signal failure" {
+		t.Errorf("loaded state = %+v, want severity 9 with reason", got)
+	}
+}
+
+func TestStateUpdateDisruptions(t *testing.T) {
+	now := time.Now()
+	state := &State{Lines: make(map[string]LineState)}
+
+	signal := tfl.Disruption{Category: "RealTime", CategoryDescription: "RealTime", Description: "Signal failure at Bank"}
+	works := tfl.Disruption{Category: "PlannedWork", CategoryDescription: "PlannedWork", Description: "No service this weekend"}
+
+	if changes := state.UpdateDisruptions([]tfl.Disruption{signal}, now); len(changes) != 0 {
+		t.Fatalf("baseline poll reported %d changes, want 0", len(changes))
+	}
+
+	changes := state.UpdateDisruptions([]tfl.Disruption{signal, works}, now)
+	if len(changes) != 1 || changes[0].Cleared || changes[0].Disruption.Description != works.Description {
+		t.Errorf("new disruption changes = %+v", changes)
+	}
+
+	changes = state.UpdateDisruptions([]tfl.Disruption{works}, now)
+	if len(changes) != 1 || !changes[0].Cleared || changes[0].Disruption.Description != signal.Description {
+		t.Errorf("cleared disruption changes = %+v", changes)
+	}
+
+	if changes := state.UpdateDisruptions([]tfl.Disruption{works}, now); len(changes) != 0 {
+		t.Errorf("unchanged poll reported %d changes, want 0", len(changes))
+	}
+}
@@ -0,0 +1,149 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// PayloadVersion is bumped whenever a field of Payload changes meaning or
+// is removed. Adding fields does not bump it.
+const PayloadVersion = 1
+
+// Payload is the body POSTed for a change. The severity fields are set for
+// every line_status change, including severity 0 (Special Service), and
+// omitted for disruptions.
+type Payload struct {
+	Version     int       `json:"version"`
+	Type        string    `json:"type"`
+	Line        string    `json:"line,omitempty"`
+	LineID      string    `json:"line_id,omitempty"`
+	OldStatus   string    `json:"old_status,omitempty"`
+	NewStatus   string    `json:"new_status,omitempty"`
+	OldSeverity *int      `json:"old_severity,omitempty"`
+	NewSeverity *int      `json:"new_severity,omitempty"`
+	Category    string    `json:"category,omitempty"`
+	Cleared     bool      `json:"cleared,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+func NewPayload(change Change, now time.Time) Payload {
+	p := Payload{
+		Version:   PayloadVersion,
+		Type:      change.Kind,
+		Timestamp: now.UTC(),
+	}
+
+	if change.Kind == KindDisruption {
+		p.Category = change.Disruption.Category
+		p.Cleared = change.Cleared
+		p.Reason = change.Disruption.Description
+		return p
+	}
+
+	p.Line = change.New.Line
+	p.LineID = change.LineID
+	p.OldStatus = change.Old.Status
+	p.NewStatus = change.New.Status
+	oldSeverity, newSeverity := change.Old.Severity, change.New.Severity
+	p.OldSeverity = &oldSeverity
+	p.NewSeverity = &newSeverity
+	p.Reason = change.New.Reason
+	return p
+}
+
+// Sign returns the hex HMAC-SHA256 of body, sent as "sha256=<hex>" in the
+// X-TfL-Signature header so receivers can verify the sender.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookSink POSTs a JSON Payload to URL for each change. Requests that
+// fail with a network error, 429 or 5xx are retried with exponential
+// backoff. With DryRun set the payload is written to Out instead.
+type WebhookSink struct {
+	URL     string
+	Secret  string
+	Retries int
+	Backoff time.Duration
+	DryRun  bool
+	Out     io.Writer
+	Client  *http.Client
+}
+
+func (s WebhookSink) Notify(change Change) error {
+	body, err := json.Marshal(NewPayload(change, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	if s.DryRun {
+		fmt.Fprintf(s.Out, "POST %s\n", s.URL)
+		if s.Secret != "" {
+			fmt.Fprintf(s.Out, "X-TfL-Signature: sha256=%s\n", Sign([]byte(s.Secret), body))
+		}
+		fmt.Fprintf(s.Out, "%s\n", body)
+		return nil
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	backoff := s.Backoff
+	var lastErr error
+	for attempt := 0; attempt <= s.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		retry, err := s.send(client, change.Kind, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return fmt.Errorf("webhook %s: %w", s.URL, lastErr)
+}
+
+// send makes a single delivery attempt and reports whether a failure is
+// worth retrying.
+func (s WebhookSink) send(client *http.Client, kind string, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tfl-cli")
+	req.Header.Set("X-TfL-Event", kind)
+	if s.Secret != "" {
+		req.Header.Set("X-TfL-Signature", "sha256="+Sign([]byte(s.Secret), body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("status %d", resp.StatusCode)
+}
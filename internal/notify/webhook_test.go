@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookSinkNotify(t *testing.T) {
+	change := Change{
+		Kind:   KindLineStatus,
+		LineID: "central",
+		Old:    LineState{Line: "Central", Status: "Good Service", Severity: 10},
+		New:    LineState{Line: "Central", Status: "Minor Delays", Severity: 9, Reason: "Signal failure"},
+	}
+
+	tests := []struct {
+		name      string
+		failures  int
+		status    int
+		retries   int
+		wantCalls int32
+		wantError bool
+	}{
+		{"delivered first time", 0, http.StatusOK, 2, 1, false},
+		{"retried after server error", 2, http.StatusInternalServerError, 2, 3, false},
+		{"gives up after retries", 5, http.StatusBadGateway, 2, 3, true},
+		{"client error not retried", 5, http.StatusBadRequest, 2, 1, true},
+		{"rate limit retried", 1, http.StatusTooManyRequests, 1, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&calls, 1)
+				body, _ := io.ReadAll(r.Body)
+
+				if got, want := r.Header.Get("X-TfL-Signature"), "sha256="+Sign([]byte("secret"), body); got != want {
+					t.Errorf("signature = %q, want %q", got, want)
+				}
+
+				var p Payload
+				if err := json.Unmarshal(body, &p); err != nil {
+					t.Errorf("invalid payload: %v", err)
+				}
+				if p.Version != PayloadVersion || p.LineID != "central" || p.NewSeverity == nil || *p.NewSeverity != 9 {
+					t.Errorf("unexpected payload %+v", p)
+				}
+
+				if int(n) <= tt.failures {
+					w.WriteHeader(tt.status)
+				}
+			}))
+			defer server.Close()
+
+			sink := WebhookSink{URL: server.URL, Secret: "secret", Retries: tt.retries, Backoff: time.Millisecond}
+			err := sink.Notify(change)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Notify() error = %v, wantError %v", err, tt.wantError)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("server called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWebhookSinkDryRun(t *testing.T) {
+	var out strings.Builder
+	sink := WebhookSink{URL: "http://example.invalid/hook", Secret: "secret", DryRun: true, Out: &out}
+
+	change := Change{Kind: KindDisruption, Disruption: DisruptionState{Category: "Planned Work", Description: "No service"}}
+	if err := sink.Notify(change); err != nil {
+		t.Fatalf("Notify() error: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{"POST http://example.invalid/hook", "X-TfL-Signature: sha256=", `"type":"disruption"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("dry run output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestPayloadSeverity(t *testing.T) {
+	change := Change{
+		Kind:   KindLineStatus,
+		LineID: "london-overground",
+		Old:    LineState{Line: "London Overground", Status: "Special Service", Severity: 0},
+		New:    LineState{Line: "London Overground", Status: "Good Service", Severity: 10},
+	}
+	body, err := json.Marshal(NewPayload(change, time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), `"old_severity":0`) {
+		t.Errorf("severity 0 missing from line status payload: %s", body)
+	}
+
+	body, err = json.Marshal(NewPayload(Change{Kind: KindDisruption}, time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), "severity") {
+		t.Errorf("disruption payload has severity: %s", body)
+	}
+}
@@ -0,0 +1,14 @@
+package tfl
+
+// Arrival sources. Live predictions come from the Arrivals API; timetable
+// entries are built from the Timetable API and have no platform or vehicle.
+const (
+	SourceRealtime  = "realtime"
+	SourceTimetable = "timetable"
+)
+
+// Scheduled reports whether the arrival comes from the timetable rather
+// than a live prediction.
+func (a Arrival) Scheduled() bool {
+	return a.Source == SourceTimetable
+}
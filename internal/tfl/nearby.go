@@ -0,0 +1,70 @@
+package tfl
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// nearbyStopTypes limits radius searches to passenger-facing stops rather
+// than every entrance and platform node.
+const nearbyStopTypes = "NaptanMetroStation,NaptanRailStation,NaptanPublicBusCoachTram,NaptanFerryPort"
+
+type LineIdentifier struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type NearbyStopPoint struct {
+	ID       string           `json:"naptanId"`
+	Name     string           `json:"commonName"`
+	Distance float64          `json:"distance"`
+	Lat      float64          `json:"lat"`
+	Lon      float64          `json:"lon"`
+	Modes    []string         `json:"modes"`
+	Lines    []LineIdentifier `json:"lines"`
+}
+
+type nearbyResponse struct {
+	StopPoints []NearbyStopPoint `json:"stopPoints"`
+}
+
+// GetStopPointsNearby returns stops within radius metres of the given
+// coordinates, closest first. An empty modes slice matches every mode.
+func (c *Client) GetStopPointsNearby(lat, lon float64, radius int, modes []string) ([]NearbyStopPoint, error) {
+	params := url.Values{}
+	params.Set("lat", fmt.Sprintf("%f", lat))
+	params.Set("lon", fmt.Sprintf("%f", lon))
+	params.Set("radius", fmt.Sprintf("%d", radius))
+	params.Set("stopTypes", nearbyStopTypes)
+	if len(modes) > 0 {
+		params.Set("modes", strings.Join(modes, ","))
+	}
+
+	var resp nearbyResponse
+	if err := c.get("/StopPoint?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(resp.StopPoints, func(i, j int) bool {
+		return resp.StopPoints[i].Distance < resp.StopPoints[j].Distance
+	})
+
+	return resp.StopPoints, nil
+}
+
+// BearingFrom returns the initial compass bearing in degrees (0-360) from
+// the given coordinates to the stop.
+func (s NearbyStopPoint) BearingFrom(lat, lon float64) float64 {
+	lat1 := lat * math.Pi / 180
+	lat2 := s.Lat * math.Pi / 180
+	dLon := (s.Lon - lon) * math.Pi / 180
+
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+
+	deg := math.Atan2(y, x) * 180 / math.Pi
+	return math.Mod(deg+360, 360)
+}
@@ -0,0 +1,38 @@
+package tfl
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBearingFrom(t *testing.T) {
+	const lat, lon = 51.5, -0.1
+
+	tests := []struct {
+		name     string
+		stop     NearbyStopPoint
+		want     float64
+		tolerant bool
+	}{
+		{"same place", NearbyStopPoint{Lat: lat, Lon: lon}, 0, false},
+		{"due north", NearbyStopPoint{Lat: lat + 0.01, Lon: lon}, 0, false},
+		{"due south", NearbyStopPoint{Lat: lat - 0.01, Lon: lon}, 180, false},
+		{"east", NearbyStopPoint{Lat: lat, Lon: lon + 0.01}, 90, true},
+		{"west wraps to 0-360", NearbyStopPoint{Lat: lat, Lon: lon - 0.01}, 270, true},
+		{"just west of north", NearbyStopPoint{Lat: lat + 0.01, Lon: lon - 0.0001}, 359.6, true},
+	}
+
+	for _, tt := range tests {
+		got := tt.stop.BearingFrom(lat, lon)
+		if got < 0 || got >= 360 {
+			t.Errorf("%s: bearing %v outside [0, 360)", tt.name, got)
+		}
+		tolerance := 1e-9
+		if tt.tolerant {
+			tolerance = 0.1
+		}
+		if math.Abs(got-tt.want) > tolerance {
+			t.Errorf("%s: bearing = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
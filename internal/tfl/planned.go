@@ -0,0 +1,23 @@
+package tfl
+
+import (
+	"fmt"
+	"time"
+)
+
+// plannedDateFormat is the date layout the Status range endpoint expects.
+const plannedDateFormat = "2006-01-02T15:04:05"
+
+// GetTubeStatusBetween returns the tube and Elizabeth line statuses in
+// effect at any point between from and to, including planned closures.
+// Each status carries the validity periods it applies to.
+func (c *Client) GetTubeStatusBetween(from, to time.Time) ([]LineStatus, error) {
+	path := fmt.Sprintf("/Line/Mode/tube,elizabeth-line/Status/%s/to/%s",
+		from.UTC().Format(plannedDateFormat), to.UTC().Format(plannedDateFormat))
+
+	var statuses []LineStatus
+	if err := c.get(path, &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
@@ -0,0 +1,55 @@
+package tfl
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type RouteStop struct {
+	ID    string           `json:"id"`
+	Name  string           `json:"name"`
+	Zone  string           `json:"zone"`
+	Lines []LineIdentifier `json:"lines"`
+}
+
+type StopPointSequence struct {
+	BranchID      int         `json:"branchId"`
+	NextBranchIDs []int       `json:"nextBranchIds"`
+	PrevBranchIDs []int       `json:"prevBranchIds"`
+	StopPoints    []RouteStop `json:"stopPoint"`
+}
+
+type OrderedRoute struct {
+	Name        string   `json:"name"`
+	NaptanIDs   []string `json:"naptanIds"`
+	ServiceType string   `json:"serviceType"`
+}
+
+type RouteSequence struct {
+	LineID             string              `json:"lineId"`
+	LineName           string              `json:"lineName"`
+	Direction          string              `json:"direction"`
+	StopPointSequences []StopPointSequence `json:"stopPointSequences"`
+	OrderedLineRoutes  []OrderedRoute      `json:"orderedLineRoutes"`
+}
+
+// GetRouteSequence returns the ordered stops of a line in one direction
+// ("inbound" or "outbound"), including every branch.
+func (c *Client) GetRouteSequence(lineID, direction string) (*RouteSequence, error) {
+	var seq RouteSequence
+	if err := c.get(fmt.Sprintf("/Line/%s/Route/Sequence/%s", url.PathEscape(lineID), url.PathEscape(direction)), &seq); err != nil {
+		return nil, err
+	}
+	return &seq, nil
+}
+
+// Stops indexes every stop that appears on any branch of the sequence.
+func (r *RouteSequence) Stops() map[string]RouteStop {
+	stops := make(map[string]RouteStop)
+	for _, seq := range r.StopPointSequences {
+		for _, sp := range seq.StopPoints {
+			stops[sp.ID] = sp
+		}
+	}
+	return stops
+}
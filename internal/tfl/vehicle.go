@@ -0,0 +1,43 @@
+package tfl
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// GetVehicleArrivals returns the predictions for every stop a vehicle has
+// yet to call at, soonest first.
+func (c *Client) GetVehicleArrivals(vehicleID string) ([]Arrival, error) {
+	var arrivals []Arrival
+	if err := c.get("/Vehicle/"+url.PathEscape(vehicleID)+"/Arrivals", &arrivals); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(arrivals, func(i, j int) bool {
+		return arrivals[i].ExpectedArrival.Before(arrivals[j].ExpectedArrival)
+	})
+
+	return arrivals, nil
+}
+
+// VehicleTrip picks one train out of a vehicle's predictions. Train numbers
+// are only unique within a line, so /Vehicle/{id}/Arrivals can mix several
+// trains; this keeps those on lineID (or the soonest arrival's line when
+// lineID is empty) that are heading where the soonest of them is.
+func VehicleTrip(arrivals []Arrival, lineID string) []Arrival {
+	var trip []Arrival
+	for _, a := range arrivals {
+		if lineID == "" {
+			lineID = a.LineID
+		}
+		if !strings.EqualFold(a.LineID, lineID) {
+			continue
+		}
+		if len(trip) > 0 && a.DestinationName != "" && trip[0].DestinationName != "" && a.DestinationName != trip[0].DestinationName {
+			continue
+		}
+		trip = append(trip, a)
+	}
+	return trip
+}
@@ -0,0 +1,39 @@
+package tfl
+
+import "testing"
+
+func TestVehicleTrip(t *testing.T) {
+	arrivals := []Arrival{
+		{LineID: "central", DestinationName: "Epping", StationName: "Bethnal Green"},
+		{LineID: "northern", DestinationName: "Morden", StationName: "Kennington"},
+		{LineID: "central", DestinationName: "Epping", StationName: "Mile End"},
+		{LineID: "central", DestinationName: "West Ruislip", StationName: "Holborn"},
+		{LineID: "central", StationName: "Stratford"},
+		{LineID: "northern", DestinationName: "Morden", StationName: "Oval"},
+	}
+
+	tests := []struct {
+		name   string
+		lineID string
+		want   []string
+	}{
+		{"soonest arrival's line", "", []string{"Bethnal Green", "Mile End", "Stratford"}},
+		{"given line", "northern", []string{"Kennington", "Oval"}},
+		{"line is case-insensitive", "Northern", []string{"Kennington", "Oval"}},
+		{"no such line", "victoria", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := VehicleTrip(arrivals, tt.lineID)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d arrivals, want %q", len(got), tt.want)
+			}
+			for i, a := range got {
+				if a.StationName != tt.want[i] {
+					t.Errorf("arrival %d = %q, want %q", i, a.StationName, tt.want[i])
+				}
+			}
+		})
+	}
+}
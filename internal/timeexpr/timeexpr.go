@@ -0,0 +1,179 @@
+// Package timeexpr parses the time expressions accepted by --time and
+// --until: clock times, relative offsets and day names.
+package timeexpr
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Examples lists valid forms, for help text and error messages.
+const Examples = `14:30, 9am, 9:30pm, now, +20m, in 1h, in 1h30m, 21:15 tomorrow, monday 8am, next monday 08:00`
+
+var (
+	clock24 = regexp.MustCompile(`^([01]?[0-9]|2[0-3]):([0-5][0-9])$`)
+	clock12 = regexp.MustCompile(`^(1[0-2]|0?[1-9])(?::([0-5][0-9]))?(am|pm)$`)
+	durPart = regexp.MustCompile(`^([0-9]+)\s*(hours|hour|hrs|hr|h|minutes|minute|mins|min|m)`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// Parse resolves a time expression relative to now, in now's location.
+//
+// A clock time on its own ("14:30", "9am") is today, even if it has
+// passed. A day can come before or after the clock time: "today",
+// "tomorrow", a weekday (today if it is that day, otherwise the next one)
+// or "next <weekday>" (always after today). "now", "+20m" and "in 1h30m"
+// are offsets from now.
+func Parse(s string, now time.Time) (time.Time, error) {
+	expr := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	t, err := parse(expr, now)
+	if err != nil {
+		if expr == "" {
+			return time.Time{}, fmt.Errorf("empty time; use e.g. %s", Examples)
+		}
+		return time.Time{}, fmt.Errorf("invalid time %q: %v; use e.g. %s", s, err, Examples)
+	}
+	return t, nil
+}
+
+func parse(expr string, now time.Time) (time.Time, error) {
+	switch {
+	case expr == "":
+		return time.Time{}, errors.New("empty")
+	case expr == "now":
+		return now, nil
+	case strings.HasPrefix(expr, "+"):
+		d, err := parseDuration(strings.TrimSpace(expr[1:]))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	case strings.HasPrefix(expr, "in "):
+		d, err := parseDuration(expr[3:])
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+
+	var clock, day []string
+	words := strings.Fields(expr)
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		switch {
+		case w == "next" && i+1 < len(words):
+			day = append(day, w, words[i+1])
+			i++
+		case w == "today" || w == "tomorrow":
+			day = append(day, w)
+		case isWeekday(w):
+			day = append(day, w)
+		default:
+			clock = append(clock, w)
+		}
+	}
+
+	if len(clock) == 0 {
+		return time.Time{}, errors.New("missing a clock time")
+	}
+	hour, minute, err := parseClock(strings.Join(clock, ""))
+	if err != nil {
+		return time.Time{}, err
+	}
+	date, err := parseDay(day, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, now.Location()), nil
+}
+
+func isWeekday(w string) bool {
+	_, ok := weekdays[w]
+	return ok
+}
+
+// parseClock parses "14:30", "9am" or "9:30 pm" (with spaces removed).
+func parseClock(s string) (hour, minute int, err error) {
+	if m := clock24.FindStringSubmatch(s); m != nil {
+		hour, _ = strconv.Atoi(m[1])
+		minute, _ = strconv.Atoi(m[2])
+		return hour, minute, nil
+	}
+	if m := clock12.FindStringSubmatch(s); m != nil {
+		hour, _ = strconv.Atoi(m[1])
+		if m[2] != "" {
+			minute, _ = strconv.Atoi(m[2])
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+		return hour, minute, nil
+	}
+	return 0, 0, fmt.Errorf("unrecognised clock time %q", s)
+}
+
+// parseDay returns the date named by the day words, or today if there are
+// none.
+func parseDay(words []string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch len(words) {
+	case 0:
+		return today, nil
+	case 1:
+		switch w := words[0]; w {
+		case "today":
+			return today, nil
+		case "tomorrow":
+			return today.AddDate(0, 0, 1), nil
+		default:
+			days := (int(weekdays[w]) - int(today.Weekday()) + 7) % 7
+			return today.AddDate(0, 0, days), nil
+		}
+	case 2:
+		if wd, ok := weekdays[words[1]]; ok && words[0] == "next" {
+			days := (int(wd) - int(today.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, days), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised day %q", strings.Join(words, " "))
+}
+
+// parseDuration parses "20m", "1h30m", "90 mins" or "1 hour 15 minutes".
+func parseDuration(s string) (time.Duration, error) {
+	rest := strings.TrimSpace(s)
+	if rest == "" {
+		return 0, errors.New("missing duration")
+	}
+	var d time.Duration
+	for rest != "" {
+		m := durPart.FindStringSubmatch(rest)
+		if m == nil {
+			return 0, fmt.Errorf("unrecognised duration %q", s)
+		}
+		n, _ := strconv.Atoi(m[1])
+		if strings.HasPrefix(m[2], "h") {
+			d += time.Duration(n) * time.Hour
+		} else {
+			d += time.Duration(n) * time.Minute
+		}
+		rest = strings.TrimSpace(rest[len(m[0]):])
+	}
+	return d, nil
+}
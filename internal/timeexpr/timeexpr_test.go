@@ -0,0 +1,90 @@
+package timeexpr
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// Wednesday 6 March 2024, 10:15 in London.
+	loc, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		loc = time.UTC
+	}
+	now := time.Date(2024, 3, 6, 10, 15, 30, 0, loc)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 3, day, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name      string
+		expr      string
+		want      time.Time
+		wantError bool
+	}{
+		{"valid time", "14:30", at(6, 14, 30), false},
+		{"midnight", "00:00", at(6, 0, 0), false},
+		{"end of day", "23:59", at(6, 23, 59), false},
+		{"single digit hour", "2:30", at(6, 2, 30), false},
+		{"past time stays today", "08:00", at(6, 8, 0), false},
+		{"now", "now", now, false},
+		{"now uppercase", " NOW ", now, false},
+		{"plus minutes", "+20m", now.Add(20 * time.Minute), false},
+		{"plus hours and minutes", "+1h30m", now.Add(90 * time.Minute), false},
+		{"in an hour", "in 1h", now.Add(time.Hour), false},
+		{"in words", "in 1 hour 15 minutes", now.Add(75 * time.Minute), false},
+		{"in mins", "in 45 mins", now.Add(45 * time.Minute), false},
+		{"am", "9am", at(6, 9, 0), false},
+		{"pm with minutes", "9:30pm", at(6, 21, 30), false},
+		{"pm with space", "9:30 pm", at(6, 21, 30), false},
+		{"noon", "12pm", at(6, 12, 0), false},
+		{"midnight 12am", "12am", at(6, 0, 0), false},
+		{"today", "today 18:00", at(6, 18, 0), false},
+		{"tomorrow after", "21:15 tomorrow", at(7, 21, 15), false},
+		{"tomorrow before", "tomorrow 7am", at(7, 7, 0), false},
+		{"weekday later this week", "friday 08:00", at(8, 8, 0), false},
+		{"weekday is today", "wednesday 18:00", at(6, 18, 0), false},
+		{"weekday earlier in week", "mon 8am", at(11, 8, 0), false},
+		{"next weekday", "next monday 08:00", at(11, 8, 0), false},
+		{"next same weekday", "next wednesday 08:00", at(13, 8, 0), false},
+		{"invalid format no colon", "1430", time.Time{}, true},
+		{"invalid hour", "25:00", time.Time{}, true},
+		{"invalid minute", "14:60", time.Time{}, true},
+		{"invalid 12 hour", "13pm", time.Time{}, true},
+		{"empty string", "", time.Time{}, true},
+		{"day without time", "tomorrow", time.Time{}, true},
+		{"bad duration", "+20x", time.Time{}, true},
+		{"in nothing", "in", time.Time{}, true},
+		{"next without weekday", "next week 08:00", time.Time{}, true},
+		{"two days", "today tomorrow 08:00", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.expr, now)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("Parse(%q) = %v, expected error", tt.expr, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.expr, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrorSuggestsForms(t *testing.T) {
+	_, err := Parse("half past nine", time.Now())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "+20m") || !strings.Contains(err.Error(), "9am") {
+		t.Errorf("error %q does not suggest valid forms", err)
+	}
+}